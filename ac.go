@@ -0,0 +1,103 @@
+package ads111x
+
+import (
+	"errors"
+	"math"
+)
+
+// ACHysteresis is the fraction of the peak-to-peak voltage a signal must
+// move past its DC level to count as a zero crossing.
+const ACHysteresis = 0.05
+
+// ACStats are measurements of an AC signal.
+type ACStats struct {
+	// DC is the mean voltage.
+	DC float64
+	// RMS is the true RMS voltage, including DC.
+	RMS float64
+	// ACRMS is the RMS voltage with DC removed.
+	ACRMS float64
+	// Min and Max are the lowest and highest voltages.
+	Min, Max float64
+	// PeakToPeak is Max - Min.
+	PeakToPeak float64
+	// CrestFactor is the largest deviation from DC divided by ACRMS. It's
+	// ~1.414 for a sine wave.
+	CrestFactor float64
+	// Frequency is the fundamental frequency in Hz measured between rising
+	// zero crossings, or 0 if fewer than two were found.
+	Frequency float64
+	// FrequencyTolerance is the +/- uncertainty of Frequency in Hz. Without
+	// per-sample timestamps it's dominated by DataRateTolerance, the device
+	// clock's error.
+	FrequencyTolerance float64
+	// Cycles is the number of whole cycles Frequency was measured over.
+	Cycles int
+}
+
+// AnalyzeAC returns AC measurements for the samples. Frequencies at or
+// above half the sample rate alias and can't be measured.
+func AnalyzeAC(s *Samples) (ACStats, error) {
+	v := s.Volts
+	if len(v) < 3 {
+		return ACStats{}, errors.New("need at least 3 samples")
+	}
+
+	st := ACStats{Min: v[0], Max: v[0]}
+	var sum, sumSq float64
+	for _, x := range v {
+		sum += x
+		sumSq += x * x
+		st.Min = math.Min(st.Min, x)
+		st.Max = math.Max(st.Max, x)
+	}
+	n := float64(len(v))
+	st.DC = sum / n
+	st.RMS = math.Sqrt(sumSq / n)
+	st.PeakToPeak = st.Max - st.Min
+
+	var acSq float64
+	for _, x := range v {
+		d := x - st.DC
+		acSq += d * d
+	}
+	st.ACRMS = math.Sqrt(acSq / n)
+	if st.ACRMS > 0 {
+		peak := math.Max(st.Max-st.DC, st.DC-st.Min)
+		st.CrestFactor = peak / st.ACRMS
+	}
+
+	// Find rising crossings of the DC level, using hysteresis so noise near
+	// the DC level doesn't count as extra crossings.
+	h := st.PeakToPeak * ACHysteresis
+	var crossings []float64
+	armed := false
+	for i := 1; i < len(v); i++ {
+		if v[i-1] < st.DC-h {
+			armed = true
+		}
+		if armed && v[i-1] < st.DC && v[i] >= st.DC {
+			// Interpolate the time the signal crossed the DC level.
+			t0, t1 := s.at(i-1), s.at(i)
+			frac := (st.DC - v[i-1]) / (v[i] - v[i-1])
+			crossings = append(crossings, t0+frac*(t1-t0))
+			armed = false
+		}
+	}
+	if len(crossings) < 2 {
+		return st, nil
+	}
+
+	span := crossings[len(crossings)-1] - crossings[0]
+	st.Cycles = len(crossings) - 1
+	st.Frequency = float64(st.Cycles) / span
+	if s.timed() {
+		// The timestamps are when the device finished each conversion, so
+		// its clock's error is measured rather than assumed. What's left is
+		// placing each end of the span, to within a sample.
+		st.FrequencyTolerance = st.Frequency / s.MeasuredRate() / span
+	} else {
+		st.FrequencyTolerance = st.Frequency * DataRateTolerance
+	}
+	return st, nil
+}
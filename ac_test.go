@@ -0,0 +1,90 @@
+package ads111x
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_AnalyzeAC(t *testing.T) {
+	// 50 Hz, 0.5 V amplitude on a 1 V DC offset.
+	s := &Samples{DataRate: DR_860SPS, Volts: sine(860, 860, 50, 0.5, 1)}
+	st, err := AnalyzeAC(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, got, exp, tol float64) {
+		if math.Abs(got-exp) > tol {
+			t.Fatalf("%s: exp = %f, got = %f", name, exp, got)
+		}
+	}
+	check("DC", st.DC, 1, 1e-3)
+	check("RMS", st.RMS, math.Sqrt(1+0.125), 1e-3)
+	check("ACRMS", st.ACRMS, 0.5/math.Sqrt2, 1e-3)
+	check("PeakToPeak", st.PeakToPeak, 1, 1e-2)
+	check("CrestFactor", st.CrestFactor, math.Sqrt2, 1e-2)
+	check("Frequency", st.Frequency, 50, 0.05)
+	check("FrequencyTolerance", st.FrequencyTolerance, 5, 0.01)
+
+	// The device runs 5% slow. The offsets Sample records reveal the actual
+	// rate; assuming the nominal one reads the signal 5% fast, within the
+	// tolerance.
+	clock := useFakeClock()
+	defer clock.restore()
+	t0 := clock.t
+	dev := fakedev.New()
+	dev.SetSpeed(0.95)
+	dev.SetConvert(func(_ uint16, t time.Time) uint16 {
+		v := 1 + 0.5*math.Sin(2*math.Pi*50*t.Sub(t0).Seconds())
+		return uint16(int16(math.Round(v / 2.048 * 32768)))
+	})
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+	if err := adc.SetDataRate(DR_860SPS); err != nil {
+		t.Fatal(err)
+	}
+	line := &fakeLine{delay: time.Duration(float64(ConversionTime(DR_860SPS)) / 0.95)}
+	adc.SetAlertLine(line)
+	if err := adc.EnableConversionReady(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err = adc.Sample(AIN_0_GND, 860); err != nil {
+		t.Fatal(err)
+	}
+	check("MeasuredRate", s.MeasuredRate(), 860*0.95, 0.1)
+	if st, err = AnalyzeAC(s); err != nil {
+		t.Fatal(err)
+	}
+	check("Frequency", st.Frequency, 50, 0.05)
+	if st.FrequencyTolerance > 0.1 {
+		t.Fatalf("exp tolerance < 0.1 Hz, got = %f", st.FrequencyTolerance)
+	}
+	s.Offsets = nil
+	if st, err = AnalyzeAC(s); err != nil {
+		t.Fatal(err)
+	}
+	check("Frequency", st.Frequency, 50/0.95, 0.05)
+	if math.Abs(st.Frequency-50) > st.FrequencyTolerance {
+		t.Fatalf("exp 50 Hz within %f Hz of %f Hz", st.FrequencyTolerance, st.Frequency)
+	}
+
+	// DC only has no frequency.
+	s = &Samples{DataRate: DR_128SPS, Volts: []float64{1, 1, 1, 1}}
+	if st, err = AnalyzeAC(s); err != nil {
+		t.Fatal(err)
+	} else if st.Frequency != 0 || st.CrestFactor != 0 {
+		t.Fatalf("unexpected stats for DC: %+v", st)
+	}
+}
+
+// sine returns n samples at rate of a sine wave at freq Hz.
+func sine(n int, rate, freq, amp, dc float64) []float64 {
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = dc + amp*math.Sin(2*math.Pi*freq*float64(i)/rate)
+	}
+	return v
+}
@@ -3,7 +3,9 @@ package ads111x
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"time"
 
 	"golang.org/x/exp/io/i2c"
 	"golang.org/x/exp/io/i2c/driver"
@@ -45,7 +47,7 @@ const (
 
 const (
 	// Busy means a conversion is currently being performed.
-	Busy = iota << Status_LSB
+	Busy Status = iota << Status_LSB
 	// Idle means a conversion is not currently being performed.
 	Idle
 )
@@ -111,7 +113,8 @@ func ScaleMinMax(fs Scale) (min, max float64) {
 		return -1.024, 1.024
 	case Scale_0_512V:
 		return -0.512, 0.512
-	case Scale_0_256V:
+	case Scale_0_256V, 6 << Scale_LSB, 7 << Scale_LSB:
+		// The reserved codes 110 and 111 are also +/- 0.256V.
 		return -0.256, 0.256
 	default:
 		panic("invalid fs value")
@@ -164,6 +167,40 @@ const (
 	DR_860SPS
 )
 
+// DataRateTolerance is the accuracy of the data rate. The internal
+// oscillator varies by up to +/- 10%.
+const DataRateTolerance = 0.10
+
+// SamplesPerSecond returns the nominal samples per second for the data rate.
+func SamplesPerSecond(dr DataRate) float64 {
+	switch dr {
+	case DR_8SPS:
+		return 8
+	case DR_16SPS:
+		return 16
+	case DR_32SPS:
+		return 32
+	case DR_64SPS:
+		return 64
+	case DR_128SPS:
+		return 128
+	case DR_250SPS:
+		return 250
+	case DR_475SPS:
+		return 475
+	case DR_860SPS:
+		return 860
+	default:
+		panic("invalid dr value")
+	}
+}
+
+// ConversionTime returns the nominal time to perform one conversion at the
+// data rate.
+func ConversionTime(dr DataRate) time.Duration {
+	return time.Duration(float64(time.Second) / SamplesPerSecond(dr))
+}
+
 type ComparatorMode uint16
 
 const (
@@ -232,14 +269,51 @@ type i2cdevice interface {
 
 // ADC represents an ADS1113, ADS1114, or ADS1115 analog to digital converter.
 type ADC struct {
-	i2c i2cdevice
+	i2c    i2cdevice
+	alert  AlertLine
+	alerts alertState
 }
 
+// Device is the read and config interface of an ADC. It's implemented by
+// ADC and by remote clients, so code written against it works with either.
+type Device interface {
+	Close() error
+	Status() (Status, error)
+	Mode() (Mode, error)
+	SetMode(m Mode) error
+	Scale() (Scale, error)
+	SetScale(fs Scale) error
+	DataRate() (DataRate, error)
+	SetDataRate(dr DataRate) error
+	ComparatorMode() (ComparatorMode, error)
+	SetComparatorMode(cm ComparatorMode) error
+	ComparatorPolarity() (ComparatorPolarity, error)
+	SetComparatorPolarity(cp ComparatorPolarity) error
+	ComparatorLatching() (ComparatorLatching, error)
+	SetComparatorLatching(cl ComparatorLatching) error
+	ComparatorQueue() (ComparatorQueue, error)
+	SetComparatorQueue(cq ComparatorQueue) error
+	Config() (uint16, error)
+	WriteConfig(cfg uint16) error
+	Thresholds() (lo, hi int16, err error)
+	SetThresholds(lo, hi int16) error
+	ReadVolts(input AIN) (float64, error)
+	ReadAIN(input AIN) (uint16, error)
+}
+
+var _ Device = (*ADC)(nil)
+
 type i2cOpener func(o driver.Opener, addr int) (*i2c.Device, error)
 
 // i2cOpen is for test purposes.
 var i2cOpen i2cOpener = i2c.Open
 
+// now and sleep are for test purposes.
+var (
+	now   = time.Now
+	sleep = time.Sleep
+)
+
 // Open returns a new ADC initialized and ready for use.
 // dev is the I2C bus device, e.g., /dev/i2c-1
 func Open(dev string, addr I2CAddress) (*ADC, error) {
@@ -253,8 +327,14 @@ func Open(dev string, addr I2CAddress) (*ADC, error) {
 	}, nil
 }
 
-// Close closes the ADC connection.
+// Close closes the ADC connection and its alert line, if any.
 func (adc *ADC) Close() error {
+	if adc.alert != nil {
+		if err := adc.alert.Close(); err != nil {
+			adc.i2c.Close()
+			return err
+		}
+	}
 	return adc.i2c.Close()
 }
 
@@ -418,54 +498,140 @@ func (adc *ADC) WriteConfig(cfg uint16) error {
 	return adc.WriteReg(ConfigReg, cfg)
 }
 
-// ReadVolts reads the voltage from the specified input.
+// Thresholds returns the Lo_thresh and Hi_thresh register values.
+func (adc *ADC) Thresholds() (lo, hi int16, err error) {
+	l, err := adc.ReadRegUint16(LoThreshReg)
+	if err != nil {
+		return 0, 0, err
+	}
+	h, err := adc.ReadRegUint16(HiThreshReg)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int16(l), int16(h), nil
+}
+
+// SetThresholds sets the comparator's Lo_thresh and Hi_thresh registers.
+func (adc *ADC) SetThresholds(lo, hi int16) error {
+	if err := adc.WriteReg(LoThreshReg, uint16(lo)); err != nil {
+		return err
+	}
+	return adc.WriteReg(HiThreshReg, uint16(hi))
+}
+
+// ReadVolts reads the voltage from the specified input. Like ReadAIN, it
+// waits for a conversion of the input.
 func (adc *ADC) ReadVolts(input AIN) (float64, error) {
 	cfg, err := adc.Config()
 	if err != nil {
 		return 0.0, err
 	}
-	cnt, err := adc.ReadAIN(input)
+	cnt, _, err := adc.readAIN(cfg, input)
 	if err != nil {
 		return 0, err
 	}
 
-	fsrange := ScaleRange(Scale(cfg & Scale_Mask))
-	voltsPerCnt := fsrange / Resolution
+	return Volts(cnt, Scale(cfg&Scale_Mask)), nil
+}
 
-	return float64(cnt) * voltsPerCnt, nil
+// Volts converts a value read from the conversion register to volts for the
+// given full scale range. The conversion register is two's complement.
+func Volts(cnt uint16, fs Scale) float64 {
+	voltsPerCnt := ScaleRange(fs) / Resolution
+	return float64(int16(cnt)) * voltsPerCnt
 }
 
-// ReadAIN reads the value from the specified input.
+// ReadAIN reads the value from the specified input. In single-shot mode it
+// starts a conversion and waits for it to finish; see Convert.
 func (adc *ADC) ReadAIN(input AIN) (uint16, error) {
 	cfg, err := adc.Config()
 	if err != nil {
 		return 0, err
 	}
-	// If the input isn't currently selected, select it.
-	currentInput := AIN(cfg & AIN_Mask)
-	if input != currentInput {
-		// Clear input select bits.
-		newConfig := cfg & ^AIN_Mask
-		// Set new input select bits.
-		newConfig |= uint16(input)
-		// Write new config.
-		if err := adc.WriteConfig(newConfig); err != nil {
-			return 0, err
-		}
+	n, _, err := adc.readAIN(cfg, input)
+	return n, err
+}
+
+// readAIN reads the value from the specified input given the current config.
+// It returns the config the device has after selecting the input.
+func (adc *ADC) readAIN(cfg uint16, input AIN) (uint16, uint16, error) {
+	want := cfg&^AIN_Mask | uint16(input)
+	n, err := adc.ConvertFrom(cfg, want)
+	if err != nil {
+		return 0, cfg, err
 	}
+	return n, want, nil
+}
 
-	// Read value from the conversion register.
-	buf := make([]byte, 2)
-	if err := adc.ReadReg(ConversionReg, buf); err != nil {
+// convPoll is how long a conversion sleeps between polls of the status.
+const convPoll = 100 * time.Microsecond
+
+// conversionTimeout returns the longest a conversion at the data rate can
+// take with the oscillator's tolerance.
+func conversionTimeout(dr DataRate) time.Duration {
+	ct := ConversionTime(dr)
+	return ct + time.Duration(float64(ct)*DataRateTolerance)
+}
+
+// Convert writes cfg, which selects the input, scale and data rate, and
+// returns a conversion made with it. In single-shot mode it starts a
+// conversion and waits for the device to go idle. In continuous mode it
+// only waits if cfg changes the config, long enough for a conversion
+// started after the change to finish.
+func (adc *ADC) Convert(cfg uint16) (uint16, error) {
+	cur, err := adc.Config()
+	if err != nil {
 		return 0, err
 	}
+	return adc.ConvertFrom(cur, cfg)
+}
 
-	var n uint16
-	if err := binary.Read(bytes.NewReader(buf), binary.BigEndian, &n); err != nil {
+// ConvertFrom is Convert given cur, the config the device has, for callers
+// that have just read it.
+func (adc *ADC) ConvertFrom(cur, cfg uint16) (uint16, error) {
+	if Mode(cfg&Mode_Mask) == Continuous {
+		if cfg&^Status_Mask != cur&^Status_Mask {
+			if err := adc.WriteConfig(cfg); err != nil {
+				return 0, err
+			}
+			// The conversion in progress when the config was written may
+			// finish with the old one, so wait for the one after it too.
+			sleep(2 * conversionTimeout(DataRate(cfg&DataRate_Mask)))
+		}
+		return adc.ReadRegUint16(ConversionReg)
+	}
+	if _, err := adc.singleShot(cfg); err != nil {
 		return 0, err
 	}
+	return adc.ReadRegUint16(ConversionReg)
+}
 
-	return n, nil
+// singleShot starts a single-shot conversion with cfg and polls the status
+// until it's finished. It returns when the device was first seen idle.
+func (adc *ADC) singleShot(cfg uint16) (time.Time, error) {
+	// Writing 1 to the status bit starts a conversion.
+	if err := adc.WriteConfig(cfg | Status_Mask); err != nil {
+		return time.Time{}, err
+	}
+	start := now()
+	dr := DataRate(cfg & DataRate_Mask)
+	// Don't poll before the fastest the conversion can finish.
+	ct := ConversionTime(dr)
+	sleep(ct - time.Duration(float64(ct)*DataRateTolerance))
+	deadline := start.Add(2 * conversionTimeout(dr))
+	for {
+		st, err := adc.Status()
+		if err != nil {
+			return time.Time{}, err
+		}
+		if st == Idle {
+			return now(), nil
+		}
+		if now().After(deadline) {
+			return time.Time{}, errors.New("timed out waiting for conversion")
+		}
+		sleep(convPoll)
+	}
 }
 
 // Read reads from the device.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/dgnorton/ads111x/internal/fakedev"
 	"golang.org/x/exp/io/i2c"
 	"golang.org/x/exp/io/i2c/driver"
 )
@@ -37,6 +38,7 @@ func Test_Open(t *testing.T) {
 	var expDev *i2c.Device = &i2c.Device{}
 	var expErr error
 
+	defer func() { i2cOpen = i2c.Open }()
 	i2cOpen = func(o driver.Opener, addr int) (*i2c.Device, error) {
 		return expDev, expErr
 	}
@@ -87,6 +89,54 @@ func Test_ReadVolts(t *testing.T) {
 	}
 }
 
+func Test_Convert(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+	dev := fakedev.New()
+	dev.SetInput(uint16(AIN_0_GND), 0x1000)
+	dev.SetInput(uint16(AIN_1_GND), 0x2000)
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+
+	// Each read converts its own input instead of returning the last
+	// conversion.
+	for _, exp := range []struct {
+		in  AIN
+		cnt uint16
+	}{{AIN_0_GND, 0x1000}, {AIN_1_GND, 0x2000}, {AIN_0_GND, 0x1000}} {
+		start := clock.t
+		if got, err := adc.ReadAIN(exp.in); err != nil {
+			t.Fatal(err)
+		} else if got != exp.cnt {
+			t.Fatalf("exp = 0x%x, got = 0x%x", exp.cnt, got)
+		}
+		if d := clock.t.Sub(start); d < ConversionTime(DR_128SPS) {
+			t.Fatalf("exp to wait for the conversion, waited %v", d)
+		}
+	}
+
+	// In continuous mode a changed input is waited for, and an unchanged
+	// one isn't.
+	if err := adc.SetMode(Continuous); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := adc.ReadAIN(AIN_1_GND); err != nil || got != 0x2000 {
+		t.Fatalf("exp = 0x2000, got = 0x%x, %v", got, err)
+	}
+	start := clock.t
+	if got, err := adc.ReadAIN(AIN_1_GND); err != nil || got != 0x2000 {
+		t.Fatalf("exp = 0x2000, got = 0x%x, %v", got, err)
+	} else if clock.t != start {
+		t.Fatalf("exp no wait, waited %v", clock.t.Sub(start))
+	}
+
+	// A device that stays busy times out.
+	dev.SetBusyPolls(1 << 20)
+	if _, err := adc.Convert(DefaultConfig); err == nil {
+		t.Fatal("expected timeout")
+	}
+}
+
 func Test_Mode(t *testing.T) {
 	adc := newTestADC()
 	defer mustClose(adc)
@@ -273,7 +323,12 @@ func Test_ScaleMinMax(t *testing.T) {
 	test(Scale_1_024V, -1.024, 1.024)
 	test(Scale_0_512V, -0.512, 0.512)
 	test(Scale_0_256V, -0.256, 0.256)
-	test(Scale_0_256V, -0.256, 0.256)
+	test(Scale(6<<Scale_LSB), -0.256, 0.256)
+	test(Scale(7<<Scale_LSB), -0.256, 0.256)
+
+	if v := Volts(0x100, Scale(7<<Scale_LSB)); v != Volts(0x100, Scale_0_256V) {
+		t.Fatalf("exp = %f, got = %f", Volts(0x100, Scale_0_256V), v)
+	}
 }
 
 func newTestADC() *ADC {
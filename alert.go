@@ -0,0 +1,196 @@
+package ads111x
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultAlertHistory is the number of alerts kept by default.
+const DefaultAlertHistory = 100
+
+// alertPoll is how long AlertPending waits for an edge on the alert line.
+const alertPoll = time.Millisecond
+
+// AlertCause is the threshold that caused an alert.
+type AlertCause int
+
+const (
+	// AlertUnknown means the conversion was between the thresholds when the
+	// alert was seen, e.g., the input recovered before the pin was seen
+	// asserted, or the pin is in conversion ready mode.
+	AlertUnknown AlertCause = iota
+	// AlertHigh means the conversion was above Hi_thresh.
+	AlertHigh
+	// AlertLow means the conversion was below Lo_thresh (window mode only).
+	AlertLow
+)
+
+func (c AlertCause) String() string {
+	switch c {
+	case AlertHigh:
+		return "high"
+	case AlertLow:
+		return "low"
+	default:
+		return "unknown"
+	}
+}
+
+// Alert is a comparator alert that was cleared.
+type Alert struct {
+	// Asserted is when the ALERT/RDY pin asserted, or when it was first
+	// seen asserted if that's unknown.
+	Asserted time.Time
+	// Cleared is when the alert was cleared.
+	Cleared time.Time
+	// Cause is the threshold that was crossed.
+	Cause AlertCause
+	// Conversion is the value read from the conversion register when the
+	// alert was seen.
+	Conversion int16
+	// Volts is Conversion in volts.
+	Volts float64
+}
+
+// alertState tracks latched alerts. It's shared by callers and goroutines
+// waiting on the alert line, so it has its own lock.
+type alertState struct {
+	mu      sync.Mutex
+	pending *Alert
+	history []Alert
+	max     int
+}
+
+// isPending returns true if an alert has been seen and not cleared.
+func (s *alertState) isPending() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pending != nil
+}
+
+// assert records a seen alert unless one is already pending.
+func (s *alertState) assert(a Alert) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		s.pending = &a
+	}
+}
+
+// clear adds the pending alert to the history, dropping the oldest if the
+// history is full, and returns it. It returns false if none is pending.
+func (s *alertState) clear(t time.Time) (Alert, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pending == nil {
+		return Alert{}, false
+	}
+	a := *s.pending
+	a.Cleared = t
+	s.pending = nil
+
+	max := s.max
+	if max == 0 {
+		max = DefaultAlertHistory
+	}
+	s.history = append(s.history, a)
+	if n := len(s.history) - max; n > 0 {
+		s.history = append(s.history[:0], s.history[n:]...)
+	}
+	return a, true
+}
+
+// alertSeen records that the ALERT/RDY pin asserted at t, reading the
+// conversion that caused it, unless an alert is already pending. Reading
+// the conversion releases a latched pin, but the alert stays pending until
+// ClearAlert is called.
+func (adc *ADC) alertSeen(t time.Time) error {
+	if adc.alerts.isPending() {
+		return nil
+	}
+	cfg, err := adc.Config()
+	if err != nil {
+		return err
+	}
+	lo, hi, err := adc.Thresholds()
+	if err != nil {
+		return err
+	}
+	cnt, err := adc.ReadRegUint16(ConversionReg)
+	if err != nil {
+		return err
+	}
+
+	a := Alert{
+		Asserted:   t,
+		Conversion: int16(cnt),
+		Volts:      Volts(cnt, Scale(cfg&Scale_Mask)),
+	}
+	switch {
+	case a.Conversion > hi:
+		a.Cause = AlertHigh
+	case ComparatorMode(cfg&ComparatorMode_Mask) == Window && a.Conversion < lo:
+		a.Cause = AlertLow
+	}
+	adc.alerts.assert(a)
+	return nil
+}
+
+// AlertPending returns true if the ALERT/RDY pin has asserted since the
+// alert was last cleared. It requires an alert line; see OpenAlert. With
+// ComparatorLatching On, the pin stays asserted until the alert is seen, and
+// the alert stays pending until ClearAlert is called.
+func (adc *ADC) AlertPending(ctx context.Context) (bool, error) {
+	if adc.alerts.isPending() {
+		return true, nil
+	}
+	if adc.alert == nil {
+		return false, ErrNoAlertLine
+	}
+	ctx, cancel := context.WithTimeout(ctx, alertPoll)
+	defer cancel()
+	t, err := adc.alert.WaitEdge(ctx)
+	if err == nil {
+		if err := adc.alertSeen(t); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return false, nil
+	}
+	return false, err
+}
+
+// ClearAlert clears a latched alert by reading the conversion register. If
+// an alert is pending, it's added to the alert history and returned, with
+// the cause decoded from the conversion read when the alert was seen.
+// Otherwise ClearAlert returns the zero Alert.
+func (adc *ADC) ClearAlert() (Alert, error) {
+	if _, err := adc.ReadRegUint16(ConversionReg); err != nil {
+		return Alert{}, err
+	}
+	a, _ := adc.alerts.clear(now())
+	return a, nil
+}
+
+// Alerts returns the cleared alerts, oldest first.
+func (adc *ADC) Alerts() []Alert {
+	adc.alerts.mu.Lock()
+	defer adc.alerts.mu.Unlock()
+	return append([]Alert(nil), adc.alerts.history...)
+}
+
+// SetAlertHistory sets the number of alerts kept in the history.
+func (adc *ADC) SetAlertHistory(n int) {
+	if n < 1 {
+		n = 1
+	}
+	adc.alerts.mu.Lock()
+	defer adc.alerts.mu.Unlock()
+	adc.alerts.max = n
+	if extra := len(adc.alerts.history) - n; extra > 0 {
+		adc.alerts.history = append(adc.alerts.history[:0], adc.alerts.history[extra:]...)
+	}
+}
@@ -0,0 +1,156 @@
+package ads111x
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_ClearAlert(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	dev := fakedev.New()
+	dev.SetReg(ConversionReg, 0x5000)
+	dev.SetReg(ConfigReg, DefaultConfig&^ComparatorMode_Mask|uint16(Window))
+	dev.SetReg(LoThreshReg, 0x1000)
+	dev.SetReg(HiThreshReg, 0x4000)
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+
+	if _, err := adc.AlertPending(context.Background()); err != ErrNoAlertLine {
+		t.Fatalf("exp = %v, got = %v", ErrNoAlertLine, err)
+	}
+
+	line := &fakeLine{edges: make(chan time.Time, 1)}
+	adc.SetAlertLine(line)
+	if pending, err := adc.AlertPending(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if pending {
+		t.Fatal("exp no pending alert")
+	}
+
+	asserted := clock.t.Add(-time.Second)
+	line.edges <- asserted
+	if pending, err := adc.AlertPending(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !pending {
+		t.Fatal("exp pending alert")
+	}
+
+	// The cause comes from the conversion read when the alert was seen, not
+	// the one read to clear it.
+	dev.SetReg(ConversionReg, 0x2000)
+	a, err := adc.ClearAlert()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Cause != AlertHigh || a.Conversion != 0x5000 || a.Volts != 1.28 || !a.Asserted.Equal(asserted) || !a.Cleared.Equal(clock.t) {
+		t.Fatalf("unexpected alert: %+v", a)
+	}
+	if pending, _ := adc.AlertPending(context.Background()); pending {
+		t.Fatal("exp alert to be cleared")
+	}
+
+	// Clearing with nothing pending doesn't add to the history.
+	if a, err = adc.ClearAlert(); err != nil {
+		t.Fatal(err)
+	} else if a != (Alert{}) || len(adc.Alerts()) != 1 {
+		t.Fatalf("unexpected alert: %+v, history: %+v", a, adc.Alerts())
+	}
+
+	// Below Lo_thresh in window mode.
+	dev.SetReg(ConversionReg, 0xf000)
+	line.edges <- clock.t
+	if _, err := adc.WaitAlert(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if a, err = adc.ClearAlert(); err != nil {
+		t.Fatal(err)
+	} else if a.Cause != AlertLow {
+		t.Fatalf("exp = %v, got = %v", AlertLow, a.Cause)
+	}
+
+	// Traditional mode only alerts on the high threshold.
+	dev.SetReg(ConfigReg, DefaultConfig)
+	line.edges <- clock.t
+	if _, err := adc.WaitAlert(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if a, err = adc.ClearAlert(); err != nil {
+		t.Fatal(err)
+	} else if a.Cause != AlertUnknown {
+		t.Fatalf("exp = %v, got = %v", AlertUnknown, a.Cause)
+	}
+
+	if h := adc.Alerts(); len(h) != 3 || h[0].Cause != AlertHigh {
+		t.Fatalf("unexpected history: %+v", h)
+	}
+	adc.SetAlertHistory(1)
+	if h := adc.Alerts(); len(h) != 1 || h[0].Cause != AlertUnknown {
+		t.Fatalf("unexpected history: %+v", h)
+	}
+}
+
+func Test_OpenAlertAsserted(t *testing.T) {
+	defer func() { openLine = OpenGPIOLine }()
+	line := &fakeLine{edges: make(chan time.Time), active: true}
+	openLine = func(chip string, offset int, edge Edge) (AlertLine, error) {
+		return line, nil
+	}
+
+	// The alert latched before the line was opened, so there's no edge.
+	dev := fakedev.New()
+	dev.SetReg(ConversionReg, 0x5000)
+	dev.SetReg(HiThreshReg, 0x4000)
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+	if err := adc.OpenAlert("/dev/gpiochip0", 17); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := adc.AlertPending(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !pending {
+		t.Fatal("exp pending alert")
+	}
+	if a, err := adc.ClearAlert(); err != nil {
+		t.Fatal(err)
+	} else if a.Cause != AlertHigh {
+		t.Fatalf("exp = %v, got = %v", AlertHigh, a.Cause)
+	}
+}
+
+func Test_AlertConcurrent(t *testing.T) {
+	dev := fakedev.New()
+	dev.SetReg(ConversionReg, 0x5000)
+	dev.SetReg(HiThreshReg, 0x4000)
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+	line := &fakeLine{edges: make(chan time.Time)}
+	adc.SetAlertLine(line)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, err := adc.WaitAlert(ctx); err != nil {
+				return
+			}
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		line.edges <- time.Unix(int64(i), 0)
+		adc.ClearAlert()
+		adc.Alerts()
+	}
+	cancel()
+	<-done
+	// The last edge's alert is either cleared or still pending.
+	adc.ClearAlert()
+	if h := adc.Alerts(); len(h) == 0 {
+		t.Fatal("exp alerts in history")
+	}
+}
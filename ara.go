@@ -0,0 +1,125 @@
+package ads111x
+
+import (
+	"context"
+	"fmt"
+)
+
+// AlertResponseAddr is the SMBus Alert Response Address. Reading from it
+// returns the address of the lowest addressed device asserting its alert
+// and makes that device release its ALERT/RDY pin.
+const AlertResponseAddr = 0x0C
+
+// maxResponses bounds the number of alert responses in one Service call so
+// a stuck device can't loop forever.
+const maxResponses = 8
+
+// AlertHandler is called with the ADC that raised an alert and the alert.
+type AlertHandler func(adc *ADC, a Alert)
+
+// ARA services alerts from several ADCs sharing one open-drain ALERT/RDY
+// line using the SMBus Alert Response Address. It's opened with
+// Bus.OpenARA so its transactions are serialized with the ADCs' on the bus.
+type ARA struct {
+	i2c      i2cdevice
+	adcs     map[I2CAddress]*ADC
+	handlers []AlertHandler
+	line     AlertLine
+}
+
+func newARA(d i2cdevice) *ARA {
+	return &ARA{
+		i2c:  d,
+		adcs: make(map[I2CAddress]*ADC),
+	}
+}
+
+// Register associates an ADC with its bus address so its alerts are
+// dispatched to it.
+func (r *ARA) Register(addr I2CAddress, adc *ADC) {
+	r.adcs[addr] = adc
+}
+
+// OnAlert registers fn to be called for each alert serviced.
+func (r *ARA) OnAlert(fn AlertHandler) {
+	r.handlers = append(r.handlers, fn)
+}
+
+// SetAlertLine sets the shared line connected to the ALERT/RDY pins, closing
+// the previous one. It's used by Run.
+func (r *ARA) SetAlertLine(line AlertLine) {
+	if r.line != nil {
+		r.line.Close()
+	}
+	r.line = line
+}
+
+// Close closes the alert response address and the alert line, if any.
+func (r *ARA) Close() error {
+	if r.line != nil {
+		r.line.Close()
+	}
+	return r.i2c.Close()
+}
+
+// Respond issues one alert response. It returns the address of the device
+// that responded, or false if no device is asserting an alert.
+func (r *ARA) Respond() (I2CAddress, bool, error) {
+	buf := make([]byte, 1)
+	if err := r.i2c.Read(buf); err != nil {
+		if IsNACK(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return I2CAddress(buf[0] >> 1), true, nil
+}
+
+// Service issues alert responses until no device responds, clearing each
+// device's alert. Alerts from registered ADCs are decoded with ClearAlert
+// and passed to the handlers. It returns the addresses that responded.
+func (r *ARA) Service() ([]I2CAddress, error) {
+	t := now()
+	var addrs []I2CAddress
+	for i := 0; i < maxResponses; i++ {
+		addr, ok, err := r.Respond()
+		if err != nil {
+			return addrs, err
+		} else if !ok {
+			return addrs, nil
+		}
+		addrs = append(addrs, addr)
+
+		adc := r.adcs[addr]
+		if adc == nil {
+			continue
+		}
+		if err := adc.alertSeen(t); err != nil {
+			return addrs, fmt.Errorf("read alert 0x%x: %w", uint8(addr), err)
+		}
+		a, err := adc.ClearAlert()
+		if err != nil {
+			return addrs, fmt.Errorf("clear alert 0x%x: %w", uint8(addr), err)
+		}
+		for _, fn := range r.handlers {
+			fn(adc, a)
+		}
+	}
+	return addrs, fmt.Errorf("alert still asserted after %d responses", maxResponses)
+}
+
+// Run waits for the shared alert line to assert and services alerts until
+// ctx is done.
+func (r *ARA) Run(ctx context.Context) error {
+	if r.line == nil {
+		return ErrNoAlertLine
+	}
+	for {
+		if _, err := r.line.WaitEdge(ctx); err != nil {
+			return err
+		}
+		if _, err := r.Service(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package ads111x
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsNACK returns true if err means no device acknowledged the address.
+// Adapter drivers report a NACK as ENXIO or EREMOTEIO. EIO is a bus error.
+func IsNACK(err error) bool {
+	return errors.Is(err, syscall.ENXIO) || errors.Is(err, syscall.EREMOTEIO)
+}
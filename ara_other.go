@@ -0,0 +1,13 @@
+//go:build !linux
+
+package ads111x
+
+import (
+	"errors"
+	"syscall"
+)
+
+// IsNACK returns true if err means no device acknowledged the address.
+func IsNACK(err error) bool {
+	return errors.Is(err, syscall.ENXIO)
+}
@@ -0,0 +1,100 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_ARA_Service(t *testing.T) {
+	// Devices at 0x48 and 0x4a are asserting; the lowest address responds
+	// first.
+	responses := []byte{0x48 << 1, 0x4a << 1}
+	ara := newARA(&mockI2C{
+		ReadFn: func(buf []byte) error {
+			if len(responses) == 0 {
+				return &errNACK{}
+			}
+			buf[0], responses = responses[0], responses[1:]
+			return nil
+		},
+	})
+
+	dev := fakedev.New()
+	dev.SetReg(ConversionReg, 0x5000)
+	dev.SetReg(HiThreshReg, 0x4000)
+	adc := newFakeADC(dev)
+	ara.Register(Addr48, adc)
+
+	var got []*ADC
+	ara.OnAlert(func(a *ADC, al Alert) {
+		if al.Cause != AlertHigh {
+			t.Fatalf("exp = %v, got = %v", AlertHigh, al.Cause)
+		}
+		got = append(got, a)
+	})
+
+	addrs, err := ara.Service()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 || addrs[0] != Addr48 || addrs[1] != Addr4A {
+		t.Fatalf("exp = [0x48 0x4a], got = %x", addrs)
+	}
+	// Only the registered ADC is dispatched to.
+	if len(got) != 1 || got[0] != adc || len(adc.Alerts()) != 1 {
+		t.Fatalf("exp alert dispatched to ADC at 0x48, got = %v", got)
+	}
+}
+
+func Test_ARA_Respond(t *testing.T) {
+	var err error
+	ara := newARA(&mockI2C{
+		ReadFn: func(buf []byte) error { return err },
+	})
+
+	err = &errNACK{}
+	if _, ok, err := ara.Respond(); err != nil || ok {
+		t.Fatalf("exp no response, got = %v, %v", ok, err)
+	}
+
+	// EIO is a bus error, not a NACK.
+	err = syscall.EIO
+	if _, _, err := ara.Respond(); err != syscall.EIO {
+		t.Fatalf("exp = %v, got = %v", syscall.EIO, err)
+	}
+}
+
+func Test_ARA_Run(t *testing.T) {
+	fail := errors.New("bus error")
+	ara := newARA(&mockI2C{
+		ReadFn: func(buf []byte) error { return fail },
+	})
+	if err := ara.Run(context.Background()); err != ErrNoAlertLine {
+		t.Fatalf("exp = %v, got = %v", ErrNoAlertLine, err)
+	}
+
+	line := &fakeLine{edges: make(chan time.Time, 1)}
+	line.edges <- time.Now()
+	ara.SetAlertLine(line)
+	if err := ara.Run(context.Background()); err != fail {
+		t.Fatalf("exp = %v, got = %v", fail, err)
+	}
+	if err := ara.Close(); err != nil {
+		t.Fatal(err)
+	} else if !line.closed {
+		t.Fatal("exp line to be closed")
+	}
+}
+
+// errNACK is a read error like the one the Linux I2C driver returns when no
+// device acknowledges.
+type errNACK struct{}
+
+func (e *errNACK) Error() string { return "no such device or address" }
+
+func (e *errNACK) Unwrap() error { return syscall.ENXIO }
@@ -0,0 +1,149 @@
+package ads111x
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotCalibrated is returned when reading a bridge that hasn't been tared
+// and calibrated.
+var ErrNotCalibrated = errors.New("bridge not calibrated")
+
+// Bridge reads a load cell or other Wheatstone bridge connected to a
+// differential input pair. Bridges put out a few millivolts, so the ADC
+// should usually be set to Scale_0_256V.
+type Bridge struct {
+	adc     *ADC
+	input   AIN
+	samples int
+
+	// zero is the bridge output in volts with no load.
+	zero float64
+	// zeroAt is when zero was captured.
+	zeroAt time.Time
+	// drift is the change in zero in volts per second between the last
+	// two tares.
+	drift float64
+	// span is units per volt.
+	span float64
+	// driftComp enables applying drift to zero between tares.
+	driftComp bool
+}
+
+// NewBridge returns a bridge on the differential input pair, AIN_0_1 or
+// AIN_2_3.
+func NewBridge(adc *ADC, input AIN) (*Bridge, error) {
+	if input != AIN_0_1 && input != AIN_2_3 {
+		return nil, fmt.Errorf("bridge input must be AIN_0_1 or AIN_2_3, got 0x%x", uint16(input))
+	}
+	return &Bridge{
+		adc:     adc,
+		input:   input,
+		samples: 1,
+	}, nil
+}
+
+// SetAveraging sets the number of conversions averaged for each reading.
+func (b *Bridge) SetAveraging(n int) {
+	if n < 1 {
+		n = 1
+	}
+	b.samples = n
+}
+
+// SetDriftCompensation enables or disables drift compensation. When
+// enabled, the zero drift measured between the last two tares is
+// extrapolated to readings taken after the most recent tare.
+func (b *Bridge) SetDriftCompensation(on bool) {
+	b.driftComp = on
+}
+
+// Tare captures the current bridge output as zero. Call it with no load.
+func (b *Bridge) Tare() error {
+	v, err := b.ReadVolts()
+	if err != nil {
+		return err
+	}
+	t := now()
+	if !b.zeroAt.IsZero() {
+		if dt := t.Sub(b.zeroAt).Seconds(); dt > 0 {
+			b.drift = (v - b.zero) / dt
+		}
+	}
+	b.zero, b.zeroAt = v, t
+	return nil
+}
+
+// Calibrate sets the span using a known load, e.g., a reference weight, on
+// the bridge. Tare must be called first.
+func (b *Bridge) Calibrate(known float64) error {
+	if b.zeroAt.IsZero() {
+		return fmt.Errorf("calibrate: %w: tare first", ErrNotCalibrated)
+	}
+	v, err := b.ReadVolts()
+	if err != nil {
+		return err
+	}
+	dv := v - b.offset(now())
+	if dv == 0 {
+		return errors.New("calibrate: bridge output didn't change under load")
+	}
+	b.span = known / dv
+	return nil
+}
+
+// SetCalibration sets the zero in volts and the span in units per volt,
+// e.g., from a previous calibration.
+func (b *Bridge) SetCalibration(zero, span float64) {
+	b.zero, b.zeroAt, b.drift = zero, now(), 0
+	b.span = span
+}
+
+// Calibration returns the zero in volts and the span in units per volt.
+func (b *Bridge) Calibration() (zero, span float64) {
+	return b.zero, b.span
+}
+
+// Read returns the load in the units used to calibrate the bridge.
+func (b *Bridge) Read() (float64, error) {
+	if b.span == 0 {
+		return 0, ErrNotCalibrated
+	}
+	v, err := b.ReadVolts()
+	if err != nil {
+		return 0, err
+	}
+	return (v - b.offset(now())) * b.span, nil
+}
+
+// ReadVolts returns the average bridge output in volts. Each sample is a
+// conversion of its own: in single-shot mode one is started for each, and
+// in continuous mode the samples are a conversion time apart.
+func (b *Bridge) ReadVolts() (float64, error) {
+	cfg, err := b.adc.Config()
+	if err != nil {
+		return 0, err
+	}
+	fs := Scale(cfg & Scale_Mask)
+	var sum float64
+	for i := 0; i < b.samples; i++ {
+		if i > 0 && Mode(cfg&Mode_Mask) == Continuous {
+			sleep(conversionTimeout(DataRate(cfg & DataRate_Mask)))
+		}
+		var cnt uint16
+		if cnt, cfg, err = b.adc.readAIN(cfg, b.input); err != nil {
+			return 0, err
+		}
+		sum += Volts(cnt, fs)
+	}
+	return sum / float64(b.samples), nil
+}
+
+// offset returns the zero at time t, compensated for drift if enabled.
+func (b *Bridge) offset(t time.Time) float64 {
+	if !b.driftComp || b.zeroAt.IsZero() {
+		return b.zero
+	}
+	return b.zero + b.drift*t.Sub(b.zeroAt).Seconds()
+}
@@ -0,0 +1,133 @@
+package ads111x
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Bridge(t *testing.T) {
+	vals := map[AIN]uint16{AIN_0_1: 0xff80} // -128 counts
+	adc := newInputTestADC(vals)
+	defer mustClose(adc)
+	if err := adc.SetScale(Scale_0_256V); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewBridge(adc, AIN_0_GND); err == nil {
+		t.Fatal("expected error for single-ended input")
+	}
+	b, err := NewBridge(adc, AIN_0_1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetAveraging(4)
+
+	if _, err := b.Read(); !errors.Is(err, ErrNotCalibrated) {
+		t.Fatalf("exp = %v, got = %v", ErrNotCalibrated, err)
+	}
+	if err := b.Tare(); err != nil {
+		t.Fatal(err)
+	}
+	// 1000 g adds 1000 counts.
+	vals[AIN_0_1] = 0xff80 + 1000 - 0x10000
+	if err := b.Calibrate(1000); err != nil {
+		t.Fatal(err)
+	}
+
+	vals[AIN_0_1] = 0xff80 + 250 - 0x10000
+	if got, err := b.Read(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got-250) > 1e-9 {
+		t.Fatalf("exp = 250, got = %f", got)
+	}
+}
+
+func Test_Bridge_Averaging(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	// Each conversion reads 100 counts more than the last.
+	var n uint16
+	dev := fakedev.New()
+	dev.SetConvert(func(uint16, time.Time) uint16 {
+		n++
+		return n * 100
+	})
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+	b, err := NewBridge(adc, AIN_0_1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetAveraging(4)
+	exp := Volts(250, Scale_2_048V)
+	if got, err := b.ReadVolts(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got-exp) > 1e-9 {
+		t.Fatalf("exp = %f, got = %f", exp, got)
+	}
+
+	// In continuous mode the samples are successive conversions too.
+	if err := adc.SetMode(Continuous); err != nil {
+		t.Fatal(err)
+	}
+	first, err := adc.ReadVolts(AIN_0_1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := b.ReadVolts(); err != nil {
+		t.Fatal(err)
+	} else if got <= first {
+		t.Fatalf("exp > %f, got = %f", first, got)
+	}
+}
+
+func Test_Bridge_Drift(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	vals := map[AIN]uint16{AIN_2_3: 0}
+	adc := newInputTestADC(vals)
+	defer mustClose(adc)
+
+	b, err := NewBridge(adc, AIN_2_3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Tare(); err != nil {
+		t.Fatal(err)
+	}
+	vals[AIN_2_3] = 100
+	if err := b.Calibrate(100); err != nil {
+		t.Fatal(err)
+	}
+
+	// Zero drifts 10 counts in 10 s.
+	clock.t = clock.t.Add(10 * time.Second)
+	vals[AIN_2_3] = 10
+	if err := b.Tare(); err != nil {
+		t.Fatal(err)
+	}
+	b.SetDriftCompensation(true)
+
+	// 5 s later the zero is expected to have drifted another 5 counts. Each
+	// conversion takes a few ms, which the drift is extrapolated over too.
+	clock.t = clock.t.Add(5 * time.Second)
+	vals[AIN_2_3] = 65
+	if got, err := b.Read(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got-50) > 0.05 {
+		t.Fatalf("exp = 50, got = %f", got)
+	}
+
+	b.SetDriftCompensation(false)
+	if got, err := b.Read(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got-55) > 1e-9 {
+		t.Fatalf("exp = 55, got = %f", got)
+	}
+}
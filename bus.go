@@ -0,0 +1,106 @@
+package ads111x
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/io/i2c/driver"
+)
+
+// Bus is an I2C bus shared by several devices. Transactions on devices
+// opened from the bus are serialized, so they can be used from different
+// goroutines.
+type Bus struct {
+	mu     sync.Mutex
+	o      driver.Opener
+	closer io.Closer
+	trace  func(Tx)
+}
+
+// Tx describes a transaction on a bus.
+type Tx struct {
+	Addr int
+	// Write and Read are the number of bytes written and read.
+	Write, Read int
+	Duration    time.Duration
+	Err         error
+}
+
+// NewBus returns a bus that opens devices with o.
+func NewBus(o driver.Opener) *Bus {
+	return &Bus{o: o}
+}
+
+// Open opens a connection to the device at addr. It implements
+// driver.Opener, so a Bus can be used wherever an Opener is.
+func (b *Bus) Open(addr int, tenbit bool) (driver.Conn, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, err := b.o.Open(addr, tenbit)
+	if err != nil {
+		return nil, err
+	}
+	return &busConn{bus: b, conn: c, addr: addr}, nil
+}
+
+// OpenADC returns an ADC at addr on the bus.
+func (b *Bus) OpenADC(addr I2CAddress) (*ADC, error) {
+	d, err := i2cOpen(b, int(addr))
+	if err != nil {
+		return nil, err
+	}
+	return &ADC{i2c: d}, nil
+}
+
+// OpenARA opens the SMBus alert response address on the bus.
+func (b *Bus) OpenARA() (*ARA, error) {
+	d, err := i2cOpen(b, AlertResponseAddr)
+	if err != nil {
+		return nil, err
+	}
+	return newARA(d), nil
+}
+
+// SetTrace sets a func that's called after every transaction on the bus,
+// or clears it if fn is nil. It's called with the bus locked, so it must not
+// use the bus.
+func (b *Bus) SetTrace(fn func(Tx)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trace = fn
+}
+
+// Close closes the bus handle, if the bus owns one. Devices opened from the
+// bus can't be used after it's closed.
+func (b *Bus) Close() error {
+	if b.closer == nil {
+		return nil
+	}
+	return b.closer.Close()
+}
+
+// busConn is a connection whose transactions hold the bus lock.
+type busConn struct {
+	bus  *Bus
+	conn driver.Conn
+	addr int
+}
+
+func (c *busConn) Tx(w, r []byte) error {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if c.bus.trace == nil {
+		return c.conn.Tx(w, r)
+	}
+	start := now()
+	err := c.conn.Tx(w, r)
+	c.bus.trace(Tx{Addr: c.addr, Write: len(w), Read: len(r), Duration: now().Sub(start), Err: err})
+	return err
+}
+
+func (c *busConn) Close() error {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	return c.conn.Close()
+}
@@ -0,0 +1,91 @@
+package ads111x
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/exp/io/i2c/driver"
+)
+
+// i2c-dev ioctl and message flags, from linux/i2c-dev.h and linux/i2c.h.
+const (
+	i2cRDWR = 0x0707
+	i2cMRD  = 0x0001
+	i2cMTEN = 0x0010
+)
+
+type i2cMsg struct {
+	addr  uint16
+	flags uint16
+	len   uint16
+	buf   *byte
+}
+
+type i2cRdwrData struct {
+	msgs  *i2cMsg
+	nmsgs uint32
+}
+
+// OpenBus opens the I2C bus device, e.g., /dev/i2c-1, with one file handle
+// shared by every device opened from the bus.
+func OpenBus(dev string) (*Bus, error) {
+	f, err := os.OpenFile(dev, os.O_RDWR, os.ModeDevice)
+	if err != nil {
+		return nil, err
+	}
+	b := NewBus(&rdwrOpener{f: f})
+	b.closer = f
+	return b, nil
+}
+
+// rdwrOpener addresses each transaction with I2C_RDWR instead of binding the
+// file handle to one address with I2C_SLAVE, so one handle serves every
+// device on the bus.
+type rdwrOpener struct {
+	f *os.File
+}
+
+func (o *rdwrOpener) Open(addr int, tenbit bool) (driver.Conn, error) {
+	c := &rdwrConn{f: o.f, addr: uint16(addr)}
+	if tenbit {
+		c.flags = i2cMTEN
+	}
+	return c, nil
+}
+
+type rdwrConn struct {
+	f     *os.File
+	addr  uint16
+	flags uint16
+}
+
+// Tx writes w and reads r in one transaction with a repeated start between
+// them.
+func (c *rdwrConn) Tx(w, r []byte) error {
+	msgs := make([]i2cMsg, 0, 2)
+	if len(w) > 0 {
+		msgs = append(msgs, i2cMsg{addr: c.addr, flags: c.flags, len: uint16(len(w)), buf: &w[0]})
+	}
+	if len(r) > 0 {
+		msgs = append(msgs, i2cMsg{addr: c.addr, flags: c.flags | i2cMRD, len: uint16(len(r)), buf: &r[0]})
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	data := i2cRdwrData{msgs: &msgs[0], nmsgs: uint32(len(msgs))}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, c.f.Fd(), i2cRDWR, uintptr(unsafe.Pointer(&data)))
+	runtime.KeepAlive(w)
+	runtime.KeepAlive(r)
+	runtime.KeepAlive(msgs)
+	if errno != 0 {
+		return &os.PathError{Op: "i2c transfer", Path: c.f.Name(), Err: errno}
+	}
+	return nil
+}
+
+// Close does nothing; the handle belongs to the bus.
+func (c *rdwrConn) Close() error {
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package ads111x
+
+import "errors"
+
+// OpenBus is only supported on Linux.
+func OpenBus(dev string) (*Bus, error) {
+	return nil, errors.New("I2C bus devices are only supported on Linux")
+}
@@ -0,0 +1,89 @@
+package ads111x
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Bus(t *testing.T) {
+	fb := newFakeBus(0x48, 0x49)
+	bus := NewBus(fb)
+	defer bus.Close()
+
+	adc, err := bus.OpenADC(Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := adc.Config(); err != nil {
+		t.Fatal(err)
+	} else if cfg != DefaultConfig {
+		t.Fatalf("exp = 0x%x, got = 0x%x", DefaultConfig, cfg)
+	}
+	if err := adc.SetScale(Scale_4_096V); err != nil {
+		t.Fatal(err)
+	}
+	if fb.Device(0x48).Reg(ConfigReg) == fb.Device(0x49).Reg(ConfigReg) {
+		t.Fatal("exp only the device at 0x48 to be written")
+	}
+
+	if _, err := bus.OpenADC(Addr4B); err == nil {
+		t.Fatal("expected error for missing device")
+	}
+}
+
+func Test_BusTrace(t *testing.T) {
+	fb := newFakeBus(0x48)
+	bus := NewBus(fb)
+	var txs []Tx
+	bus.SetTrace(func(tx Tx) { txs = append(txs, tx) })
+
+	adc, err := bus.OpenADC(Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := adc.Config(); err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].Addr != 0x48 || txs[0].Write != 1 || txs[0].Read != 2 || txs[0].Err != nil {
+		t.Fatalf("unexpected transactions: %+v", txs)
+	}
+
+	bus.SetTrace(nil)
+	adc.Config()
+	if len(txs) != 1 {
+		t.Fatalf("exp = 1 transaction, got = %d", len(txs))
+	}
+}
+
+// newFakeBus returns a bus with devices at addrs, timed by the package
+// clock.
+func newFakeBus(addrs ...int) *fakedev.Bus {
+	fb := fakedev.NewBus(addrs...)
+	for _, addr := range addrs {
+		fb.Device(addr).SetClock(pkgNow)
+	}
+	return fb
+}
+
+// newFakeADC returns an ADC on dev, timed by the package clock.
+func newFakeADC(dev *fakedev.Device) *ADC {
+	dev.SetClock(pkgNow)
+	adc, err := NewBus(dev).OpenADC(Addr48)
+	if err != nil {
+		panic(err)
+	}
+	return adc
+}
+
+// newInputTestADC returns an ADC whose conversions are of the value in vals
+// for the selected input when they finish.
+func newInputTestADC(vals map[AIN]uint16) *ADC {
+	dev := fakedev.New()
+	dev.SetConvert(func(cfg uint16, _ time.Time) uint16 { return vals[AIN(cfg&AIN_Mask)] })
+	return newFakeADC(dev)
+}
+
+// pkgNow calls now, so a fake clock is used once it's set.
+func pkgNow() time.Time { return now() }
@@ -0,0 +1,213 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TriggerType is the condition that fires a capture.
+type TriggerType int
+
+const (
+	// TriggerRising fires when the signal crosses Level going up.
+	TriggerRising TriggerType = iota
+	// TriggerFalling fires when the signal crosses Level going down.
+	TriggerFalling
+	// TriggerLevel fires on the first sample at or above Level.
+	TriggerLevel
+	// TriggerWindow fires when the signal leaves the Low..High window.
+	TriggerWindow
+)
+
+// Trigger is the condition, in volts, that fires a capture.
+type Trigger struct {
+	Type TriggerType
+	// Level is used by TriggerRising, TriggerFalling and TriggerLevel.
+	Level float64
+	// Low and High are used by TriggerWindow.
+	Low, High float64
+	// Hysteresis is how far past Level the signal must be before an edge
+	// trigger is armed, to keep noise from firing it.
+	Hysteresis float64
+}
+
+// CaptureSample is a sample with the time it was read.
+type CaptureSample struct {
+	Time  time.Time
+	Volts float64
+}
+
+// Event is the data captured around a trigger.
+type Event struct {
+	// Samples are the pre-trigger samples followed by the post-trigger
+	// samples. Samples[Trigger] is the sample that fired the trigger.
+	Samples []CaptureSample
+	// Trigger is the index of the triggering sample.
+	Trigger int
+}
+
+// TriggeredAt returns when the triggering sample was read.
+func (e *Event) TriggeredAt() time.Time {
+	return e.Samples[e.Trigger].Time
+}
+
+// Capture samples an input continuously into a ring buffer and returns the
+// samples around the point a trigger fires, like an oscilloscope.
+type Capture struct {
+	adc     *ADC
+	input   AIN
+	trigger Trigger
+	pre     int
+	post    int
+}
+
+// NewCapture returns a capture of pre samples before the trigger and post
+// samples starting with the triggering sample.
+func (adc *ADC) NewCapture(input AIN, trigger Trigger, pre, post int) (*Capture, error) {
+	if pre < 0 || post < 1 {
+		return nil, fmt.Errorf("invalid pre/post trigger counts %d/%d", pre, post)
+	}
+	if trigger.Type == TriggerWindow && trigger.Low >= trigger.High {
+		return nil, fmt.Errorf("invalid trigger window %g..%g", trigger.Low, trigger.High)
+	}
+	return &Capture{
+		adc:     adc,
+		input:   input,
+		trigger: trigger,
+		pre:     pre,
+		post:    post,
+	}, nil
+}
+
+// Once arms the trigger and returns the first event. Conversions are paced
+// as they are by Sample.
+func (c *Capture) Once(ctx context.Context) (*Event, error) {
+	var ev *Event
+	err := c.Run(ctx, func(e *Event) error {
+		ev = e
+		return errStop
+	})
+	if err == errStop {
+		return ev, nil
+	}
+	return nil, err
+}
+
+// errStop stops Run without an error.
+var errStop = errors.New("stop")
+
+// Run arms the trigger and calls fn with each event, rearming after each
+// one, until ctx is done or fn returns an error. Run returns fn's error or
+// ctx's error. The config is restored when Run returns.
+func (c *Capture) Run(ctx context.Context, fn func(*Event) error) (err error) {
+	sp, err := c.adc.newSampler(c.input)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := sp.close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	ring := make([]CaptureSample, c.pre)
+	for {
+		var (
+			n     int // samples in ring
+			head  int // next slot in ring
+			armed bool
+			prev  float64
+		)
+		// Fill the pre-trigger buffer and wait for the trigger.
+		for {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			t, v, err := sp.next(ctx)
+			if err != nil {
+				return err
+			}
+			s := CaptureSample{Time: t, Volts: v}
+
+			// The trigger isn't armed until there are enough pre-trigger
+			// samples.
+			if n == c.pre {
+				if c.fired(armed, prev, v) {
+					ev := &Event{
+						Samples: make([]CaptureSample, 0, c.pre+c.post),
+						Trigger: c.pre,
+					}
+					// The ring is full, so head is the oldest sample.
+					for i := 0; i < c.pre; i++ {
+						ev.Samples = append(ev.Samples, ring[(head+i)%c.pre])
+					}
+					ev.Samples = append(ev.Samples, s)
+					if err := c.fill(ctx, sp, ev); err != nil {
+						return err
+					}
+					if err := fn(ev); err != nil {
+						return err
+					}
+					break
+				}
+				armed = armed || c.arms(v)
+			}
+			if c.pre > 0 {
+				ring[head] = s
+				head = (head + 1) % c.pre
+				if n < c.pre {
+					n++
+				}
+			}
+			prev = v
+		}
+	}
+}
+
+// fill reads the remaining post-trigger samples into ev.
+func (c *Capture) fill(ctx context.Context, sp *sampler, ev *Event) error {
+	for len(ev.Samples) < c.pre+c.post {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t, v, err := sp.next(ctx)
+		if err != nil {
+			return err
+		}
+		ev.Samples = append(ev.Samples, CaptureSample{Time: t, Volts: v})
+	}
+	return nil
+}
+
+// arms returns true if v arms an edge trigger: the signal must be on the
+// far side of Level, past the hysteresis, before the edge counts.
+func (c *Capture) arms(v float64) bool {
+	tr := c.trigger
+	switch tr.Type {
+	case TriggerRising:
+		return v < tr.Level-tr.Hysteresis
+	case TriggerFalling:
+		return v > tr.Level+tr.Hysteresis
+	default:
+		return true
+	}
+}
+
+// fired returns true if v fires the trigger.
+func (c *Capture) fired(armed bool, prev, v float64) bool {
+	tr := c.trigger
+	switch tr.Type {
+	case TriggerRising:
+		return armed && prev < tr.Level && v >= tr.Level
+	case TriggerFalling:
+		return armed && prev > tr.Level && v <= tr.Level
+	case TriggerLevel:
+		return v >= tr.Level
+	case TriggerWindow:
+		return v < tr.Low || v > tr.High
+	default:
+		return false
+	}
+}
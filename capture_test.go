@@ -0,0 +1,94 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Capture(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	// 0x1000 counts is 0.256 V at +/- 2.048 V.
+	adc := newSeqTestADC(0, 0x1000, 0, 0x200, 0x400, 0x1000, 0x1800, 0x1000, 0x800, 0x400, 0x100, 0, 0x1000, 0x2000)
+	defer mustClose(adc)
+
+	c, err := adc.NewCapture(AIN_0_GND, Trigger{Type: TriggerRising, Level: 0.2, Hysteresis: 0.05}, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The rising edge at index 1 is ignored because the pre-trigger buffer
+	// isn't full yet.
+	ev, err := c.Once(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []float64{0.032, 0.064, 0.256, 0.384, 0.256}
+	if len(ev.Samples) != len(exp) || ev.Trigger != 2 {
+		t.Fatalf("exp = %d samples with trigger at 2, got = %d with trigger at %d", len(exp), len(ev.Samples), ev.Trigger)
+	}
+	for i, s := range ev.Samples {
+		if s.Volts != exp[i] {
+			t.Fatalf("sample %d: exp = %f, got = %f", i, exp[i], s.Volts)
+		}
+	}
+	if !ev.TriggeredAt().After(ev.Samples[1].Time) {
+		t.Fatal("exp trigger time after pre-trigger samples")
+	}
+}
+
+func Test_Capture_Run(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	adc := newSeqTestADC(0, 0x1000, 0, 0x1000, 0, 0x1000, 0, 0x1000)
+	defer mustClose(adc)
+
+	c, err := adc.NewCapture(AIN_0_GND, Trigger{Type: TriggerWindow, Low: -0.1, High: 0.1}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	stop := errors.New("stop")
+	err = c.Run(context.Background(), func(ev *Event) error {
+		if ev.Samples[0].Volts != 0.256 {
+			t.Fatalf("exp = 0.256, got = %f", ev.Samples[0].Volts)
+		}
+		if n++; n == 3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatalf("exp = %v, got = %v", stop, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Once(ctx); err != context.Canceled {
+		t.Fatalf("exp = %v, got = %v", context.Canceled, err)
+	}
+
+	if _, err := adc.NewCapture(AIN_0_GND, Trigger{Type: TriggerWindow, Low: 1, High: 0}, 0, 1); err == nil {
+		t.Fatal("expected error for invalid window")
+	}
+}
+
+// newSeqTestADC returns an ADC whose conversions are vals in order,
+// repeating the last value.
+func newSeqTestADC(vals ...uint16) *ADC {
+	dev := fakedev.New()
+	var i int
+	dev.SetConvert(func(uint16, time.Time) uint16 {
+		v := vals[i]
+		if i < len(vals)-1 {
+			i++
+		}
+		return v
+	})
+	return newFakeADC(dev)
+}
@@ -0,0 +1,94 @@
+// Command ads111x-exporter serves the channels in a config file as
+// Prometheus metrics.
+//
+// Usage:
+//
+//	ads111x-exporter -config ads.yaml [-listen :9118] [-interval 10s]
+//
+// Metrics are served at /metrics, in the OpenMetrics format for scrapers
+// that ask for it. Channels are sampled every interval, independent of
+// scrapes.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dgnorton/ads111x/config"
+	"github.com/dgnorton/ads111x/exporter"
+)
+
+var errUsage = errors.New("usage: ads111x-exporter -config file [-listen address] [-interval d]")
+
+func main() {
+	err := run(os.Args[1:])
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		// -h has already printed the usage.
+		return
+	}
+	fmt.Fprintln(os.Stderr, "ads111x-exporter:", err)
+	if errors.Is(err, errUsage) {
+		os.Exit(2)
+	}
+	os.Exit(1)
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("ads111x-exporter", flag.ContinueOnError)
+	path := fs.String("config", "", "config `file` describing the buses, ADCs and channels")
+	listen := fs.String("listen", ":9118", "HTTP listen `address`")
+	interval := fs.Duration("interval", exporter.DefaultInterval, "time between samples of each channel")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" || fs.NArg() != 0 {
+		return errUsage
+	}
+
+	d, err := config.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	e := exporter.New(d, exporter.Options{Interval: *interval})
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `<html><body><a href="/metrics">Metrics</a></body></html>`)
+	})
+	srv := &http.Server{Addr: *listen, Handler: mux}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		e.Run(ctx)
+		close(done)
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	err = srv.ListenAndServe()
+	cancel()
+	<-done
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,389 @@
+// Command ads111x reads and configures an ADS111x analog to digital
+// converter.
+//
+// Usage:
+//
+//	ads111x [-dev /dev/i2c-1] [-addr 0x48] command [arguments]
+//
+// The commands are:
+//
+//	read [-scale range] [-rate rate] input
+//	                         read an input once, e.g., read ain0-gnd
+//	watch [-interval d] [-n count] [-scale range] [-rate rate] input
+//	                         read an input repeatedly
+//	get [field]              print one or every config field
+//	set field value          set a config field, e.g., set scale 4.096V
+//	regs [-json]             print raw and decoded register values
+//	log [flags] file input...
+//	                         record inputs to a CSV or JSON Lines file; run
+//	                         "ads111x log -h" for the flags
+//	serve [-listen localhost:8080]
+//	                         serve the ADC over HTTP/JSON for remote clients;
+//	                         the API is unauthenticated, so it only listens
+//	                         on localhost by default
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/datalog"
+	"github.com/dgnorton/ads111x/remote"
+)
+
+// openADC is for test purposes.
+var openADC = ads111x.Open
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "ads111x:", err)
+		if errors.Is(err, flag.ErrHelp) || errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+var errUsage = errors.New("usage: ads111x [-dev device] [-addr address] read|watch|get|set|regs|log|serve [arguments]")
+
+func run(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("ads111x", flag.ContinueOnError)
+	dev := fs.String("dev", "/dev/i2c-1", "I2C bus `device`")
+	addr := fs.String("addr", "0x48", "I2C `address` of the ADC")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errUsage
+	}
+	a, err := strconv.ParseUint(*addr, 0, 8)
+	if err != nil {
+		return fmt.Errorf("invalid address %q", *addr)
+	}
+
+	cmd, args := fs.Arg(0), fs.Args()[1:]
+	var fn func(adc *ads111x.ADC, args []string, w io.Writer) error
+	switch cmd {
+	case "read":
+		fn = read
+	case "watch":
+		fn = watch
+	case "get":
+		fn = get
+	case "set":
+		fn = set
+	case "regs":
+		fn = regs
+	case "log":
+		fn = logInputs
+	case "serve":
+		fn = serve
+	default:
+		return fmt.Errorf("unknown command %q: %w", cmd, errUsage)
+	}
+
+	adc, err := openADC(*dev, ads111x.I2CAddress(a))
+	if err != nil {
+		return err
+	}
+	defer adc.Close()
+	return fn(adc, args, w)
+}
+
+func read(adc *ads111x.ADC, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("read", flag.ContinueOnError)
+	configure := configFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: read [-scale range] [-rate rate] input")
+	}
+	var input ads111x.AIN
+	if err := input.Set(fs.Arg(0)); err != nil {
+		return err
+	}
+	if err := configure(adc); err != nil {
+		return err
+	}
+	v, raw, err := readInput(adc, input)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%f V\t%d\n", v, raw)
+	return nil
+}
+
+func watch(adc *ads111x.ADC, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := fs.Duration("interval", time.Second, "time between reads")
+	n := fs.Int("n", 0, "number of reads, or 0 to read until interrupted")
+	configure := configFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: watch [-interval d] [-n count] [-scale range] [-rate rate] input")
+	}
+	var input ads111x.AIN
+	if err := input.Set(fs.Arg(0)); err != nil {
+		return err
+	}
+	if err := configure(adc); err != nil {
+		return err
+	}
+
+	tick := time.NewTicker(*interval)
+	defer tick.Stop()
+	for i := 0; *n == 0 || i < *n; i++ {
+		if i > 0 {
+			<-tick.C
+		}
+		v, raw, err := readInput(adc, input)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s\t%f V\t%d\n", time.Now().Format(time.RFC3339Nano), v, raw)
+	}
+	return nil
+}
+
+func get(adc *ads111x.ADC, args []string, w io.Writer) error {
+	if len(args) > 1 {
+		return errors.New("usage: get [field]")
+	}
+	cfg, err := adc.Config()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		s, err := ads111x.FieldText(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, s)
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 1, ' ', 0)
+	for _, name := range ads111x.FieldNames() {
+		s, _ := ads111x.FieldText(cfg, name)
+		fmt.Fprintf(tw, "%s\t%s\n", name, s)
+	}
+	return tw.Flush()
+}
+
+func set(adc *ads111x.ADC, args []string, w io.Writer) error {
+	if len(args) != 2 {
+		return errors.New("usage: set field value")
+	}
+	cfg, err := adc.Config()
+	if err != nil {
+		return err
+	}
+	if cfg, err = ads111x.SetFieldText(cfg, args[0], args[1]); err != nil {
+		return err
+	}
+	return adc.WriteConfig(cfg)
+}
+
+func regs(adc *ads111x.ADC, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("regs", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: regs [-json]")
+	}
+	d, err := adc.Dump()
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+	_, err = io.WriteString(w, d.String())
+	return err
+}
+
+func logInputs(adc *ads111x.ADC, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	var opts datalog.Options
+	format := fs.String("format", "csv", "file `format`, csv or jsonl")
+	columns := fs.String("columns", "", "comma separated `columns` (default all): time, channel, raw, volts, value, unit, clipped")
+	interval := fs.Duration("interval", time.Second, "time between reads")
+	n := fs.Int("n", 0, "number of reads, or 0 to read until interrupted")
+	maxSize := fs.String("max-size", "", "rotate the file at this `size`, e.g., 10M")
+	fs.DurationVar(&opts.MaxAge, "max-age", 0, "rotate the file after this long")
+	fs.BoolVar(&opts.Gzip, "gzip", false, "gzip rotated files")
+	fs.IntVar(&opts.MaxFiles, "keep", 0, "number of rotated files to keep, or 0 to keep them all")
+	sync := fs.String("sync", "rotate", "when to fsync: never, rotate, interval or always")
+	fs.DurationVar(&opts.SyncInterval, "sync-interval", 10*time.Second, "time between fsyncs for -sync interval")
+	configure := configFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return errors.New("usage: log [flags] file input...")
+	}
+	opts.Format = datalog.Format(*format)
+	opts.Sync = datalog.SyncPolicy(*sync)
+	if *columns != "" {
+		cols, err := datalog.ParseColumns(*columns)
+		if err != nil {
+			return err
+		}
+		opts.Columns = cols
+	}
+	if *maxSize != "" {
+		size, err := parseSize(*maxSize)
+		if err != nil {
+			return err
+		}
+		opts.MaxSize = size
+	}
+	inputs := make([]ads111x.AIN, fs.NArg()-1)
+	for i, arg := range fs.Args()[1:] {
+		if err := inputs[i].Set(arg); err != nil {
+			return err
+		}
+	}
+	if err := configure(adc); err != nil {
+		return err
+	}
+
+	l, err := datalog.New(fs.Arg(0), opts)
+	if err != nil {
+		return err
+	}
+	err = logRecords(l, adc, inputs, *interval, *n)
+	if cerr := l.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// logRecords logs the inputs every interval, n times or until interrupted
+// if n is 0.
+func logRecords(l *datalog.Logger, adc *ads111x.ADC, inputs []ads111x.AIN, interval time.Duration, n int) error {
+	// Stop cleanly when interrupted, so the file is synced.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	recs := make([]datalog.Record, len(inputs))
+	for i := 0; n == 0 || i < n; i++ {
+		if i > 0 {
+			select {
+			case <-tick.C:
+			case <-stop:
+				return nil
+			}
+		}
+		for j, input := range inputs {
+			v, raw, err := readInput(adc, input)
+			if err != nil {
+				return err
+			}
+			name, _ := input.MarshalText()
+			recs[j] = datalog.Record{
+				Time:    time.Now(),
+				Channel: string(name),
+				Raw:     raw,
+				Volts:   v,
+				Value:   v,
+				Unit:    "V",
+				Clipped: datalog.Clipped(raw),
+			}
+		}
+		if err := l.Write(recs...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func serve(adc *ads111x.ADC, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", "localhost:8080", "HTTP listen `address`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("usage: serve [-listen address]")
+	}
+	fmt.Fprintf(w, "serving on %s\n", *listen)
+	return http.ListenAndServe(*listen, remote.NewServer(adc))
+}
+
+// parseSize parses a size in bytes with an optional K, M or G suffix.
+func parseSize(s string) (int64, error) {
+	mult := int64(1)
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// readInput returns the volts and raw conversion value of an input.
+func readInput(adc *ads111x.ADC, input ads111x.AIN) (float64, int16, error) {
+	fs, err := adc.Scale()
+	if err != nil {
+		return 0, 0, err
+	}
+	cnt, err := adc.ReadAIN(input)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ads111x.Volts(cnt, fs), int16(cnt), nil
+}
+
+// configFlags adds flags that configure the ADC before reading, and returns
+// a func that applies the ones that were set.
+func configFlags(fs *flag.FlagSet) func(adc *ads111x.ADC) error {
+	var scale ads111x.Scale
+	var rate ads111x.DataRate
+	fs.Var(&scale, "scale", "full scale `range`, e.g., 4.096V")
+	fs.Var(&rate, "rate", "data `rate`, e.g., 860sps")
+	return func(adc *ads111x.ADC) error {
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			switch {
+			case err != nil:
+			case f.Name == "scale":
+				err = adc.SetScale(scale)
+			case f.Name == "rate":
+				err = adc.SetDataRate(rate)
+			}
+		})
+		return err
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_run(t *testing.T) {
+	dev := useFakeADC()
+	defer func() { openADC = ads111x.Open }()
+	dev.SetReg(ads111x.ConversionReg, 0x4000)
+
+	test := func(exp string, args ...string) {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := run(args, &buf); err != nil {
+			t.Fatal(err)
+		} else if got := buf.String(); !strings.Contains(got, exp) {
+			t.Fatalf("exp = %q, got = %q", exp, got)
+		}
+	}
+
+	test("1.024000 V\t16384\n", "-addr", "0x49", "read", "ain1-gnd")
+	if addr := dev.Addr(); addr != 0x49 {
+		t.Fatalf("exp = 0x49, got = 0x%x", addr)
+	}
+	if in := ads111x.AIN(dev.Reg(ads111x.ConfigReg) & ads111x.AIN_Mask); in != ads111x.AIN_1_GND {
+		t.Fatalf("exp = %v, got = %v", ads111x.AIN_1_GND, in)
+	}
+	test("0.512000 V\t16384\n", "read", "-scale", "1.024V", "-rate", "860sps", "1-gnd")
+	test("1.024V\n", "get", "scale")
+	test("860sps\n", "get", "rate")
+	test("", "set", "scale", "2.048V")
+	test("2.048V\n", "get", "scale")
+	test("", "set", "scale", "4.096V")
+	test("4.096V\n", "get", "scale")
+	test("", "set", "comp-queue", "after-two")
+	test("after-two\n", "get", "comp-queue")
+	test("", "set", "comp-queue", "disable")
+	test("comp-queue    disable\n", "get")
+	test("  PGA        [11:9]   1*      Scale_4_096V\n", "regs")
+	test(`"symbol": "AIN_1_GND"`, "regs", "-json")
+
+	var buf bytes.Buffer
+	if err := run([]string{"watch", "-interval", "1ms", "-n", "3", "ain0-gnd"}, &buf); err != nil {
+		t.Fatal(err)
+	} else if n := strings.Count(buf.String(), "\n"); n != 3 {
+		t.Fatalf("exp = 3 lines, got = %d", n)
+	}
+
+	path := filepath.Join(t.TempDir(), "log.csv")
+	if err := run([]string{"log", "-interval", "1ms", "-n", "2", "-columns", "channel,raw", path, "ain0-gnd", "ain3"}, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(path); err != nil {
+		t.Fatal(err)
+	} else if exp := "channel,raw\nain0-gnd,16384\nain3-gnd,16384\nain0-gnd,16384\nain3-gnd,16384\n"; string(b) != exp {
+		t.Fatalf("exp = %q, got = %q", exp, b)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"frob"},
+		{"-addr", "x", "read", "ain0-gnd"},
+		{"read", "ain9"},
+		{"set", "scale", "9V"},
+		{"set", "status", "busy"},
+		{"get", "nope"},
+		{"log", path},
+		{"serve", "extra"},
+		{"log", "-max-size", "10X", path, "ain0"},
+		{"log", "-format", "xml", path, "ain0"},
+	} {
+		if err := run(args, &buf); err == nil {
+			t.Fatalf("expected error for %q", args)
+		}
+	}
+}
+
+func Test_readInput(t *testing.T) {
+	dev := useFakeADC()
+	defer func() { openADC = ads111x.Open }()
+	inputs := map[ads111x.AIN]uint16{
+		ads111x.AIN_1_GND: 0x4000,
+		ads111x.AIN_2_GND: 0x2000,
+	}
+	for in, cnt := range inputs {
+		dev.SetInput(uint16(in), cnt)
+	}
+	adc, err := openADC("", ads111x.Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each read converts its own input, not the one read before it.
+	for _, in := range []ads111x.AIN{ads111x.AIN_1_GND, ads111x.AIN_2_GND, ads111x.AIN_1_GND} {
+		if _, raw, err := readInput(adc, in); err != nil {
+			t.Fatal(err)
+		} else if exp := inputs[in]; uint16(raw) != exp {
+			t.Fatalf("exp = 0x%x, got = 0x%x", exp, uint16(raw))
+		}
+	}
+}
+
+// useFakeADC makes openADC open a fake ADC.
+func useFakeADC() *fakedev.Device {
+	dev := fakedev.New()
+	openADC = func(_ string, addr ads111x.I2CAddress) (*ads111x.ADC, error) {
+		return ads111x.NewBus(dev).OpenADC(addr)
+	}
+	return dev
+}
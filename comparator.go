@@ -0,0 +1,214 @@
+package ads111x
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ComparatorEventType is the type of a ComparatorEvent.
+type ComparatorEventType int
+
+const (
+	// AlertEnter means the comparator asserted.
+	AlertEnter ComparatorEventType = iota
+	// AlertExit means the comparator deasserted.
+	AlertExit
+)
+
+func (t ComparatorEventType) String() string {
+	if t == AlertEnter {
+		return "enter"
+	}
+	return "exit"
+}
+
+// ComparatorEvent is a change in a SoftComparator's state.
+type ComparatorEvent struct {
+	Type ComparatorEventType
+	// Time is when the reading that caused the event was taken.
+	Time time.Time
+	// Volts is the reading that caused the event. It's 0 for an exit caused
+	// by Clear.
+	Volts float64
+	// High is true if an enter event was caused by crossing the high
+	// threshold and false if by the low threshold.
+	High bool
+}
+
+// SoftComparator implements the device's comparator in software, so alerts
+// can be used on boards that don't wire the ALERT/RDY pin. It has the same
+// modes as the hardware comparator:
+//
+// Traditional asserts after Queue successive readings above the high
+// threshold and deasserts when a reading is below the low threshold.
+// Window asserts after Queue successive readings outside of low..high and
+// deasserts when a reading is inside. With latching On, the comparator
+// stays asserted until Clear is called. With Disable, it never asserts.
+type SoftComparator struct {
+	mu       sync.Mutex
+	mode     ComparatorMode
+	latching ComparatorLatching
+	queue    ComparatorQueue
+	low      float64
+	high     float64
+
+	count    int
+	asserted bool
+	handlers []func(ComparatorEvent)
+	chans    []chan ComparatorEvent
+	dropped  int
+}
+
+// NewSoftComparator returns a comparator with thresholds in volts.
+func NewSoftComparator(mode ComparatorMode, latching ComparatorLatching, queue ComparatorQueue, low, high float64) (*SoftComparator, error) {
+	if low > high {
+		return nil, fmt.Errorf("low threshold %g is above high threshold %g", low, high)
+	}
+	return &SoftComparator{
+		mode:     mode,
+		latching: latching,
+		queue:    queue,
+		low:      low,
+		high:     high,
+	}, nil
+}
+
+// queueLen returns the number of successive readings needed to assert, or 0
+// if the comparator is disabled.
+func queueLen(q ComparatorQueue) int {
+	switch q {
+	case AfterOne:
+		return 1
+	case AfterTwo:
+		return 2
+	case AfterFour:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// OnEvent registers fn to be called with each event. fn is called
+// synchronously from Update and Clear and must not call them.
+func (c *SoftComparator) OnEvent(fn func(ComparatorEvent)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, fn)
+}
+
+// Events returns a channel that receives events. Events are dropped rather
+// than blocking Update if the channel's buffer is full; see Dropped.
+func (c *SoftComparator) Events(buffer int) <-chan ComparatorEvent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan ComparatorEvent, buffer)
+	c.chans = append(c.chans, ch)
+	return ch
+}
+
+// Dropped returns the number of events dropped because a channel was full.
+func (c *SoftComparator) Dropped() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dropped
+}
+
+// Asserted returns true if the comparator is asserted.
+func (c *SoftComparator) Asserted() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.asserted
+}
+
+// Update feeds a reading taken at t to the comparator.
+func (c *SoftComparator) Update(t time.Time, volts float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := queueLen(c.queue)
+	if n == 0 {
+		return
+	}
+
+	over := volts > c.high
+	under := volts < c.low
+	if c.asserted {
+		if c.latching == On {
+			return
+		}
+		var exit bool
+		if c.mode == Window {
+			exit = !over && !under
+		} else {
+			exit = under
+		}
+		if exit {
+			c.asserted, c.count = false, 0
+			c.emit(ComparatorEvent{Type: AlertExit, Time: t, Volts: volts})
+		}
+		return
+	}
+
+	if over || (c.mode == Window && under) {
+		c.count++
+	} else {
+		c.count = 0
+	}
+	if c.count >= n {
+		c.asserted = true
+		c.emit(ComparatorEvent{Type: AlertEnter, Time: t, Volts: volts, High: over})
+	}
+}
+
+// Clear deasserts a latched comparator. If the condition still holds, it
+// asserts again after Queue more readings.
+func (c *SoftComparator) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.asserted {
+		return
+	}
+	c.asserted, c.count = false, 0
+	c.emit(ComparatorEvent{Type: AlertExit, Time: now()})
+}
+
+// emit delivers an event. c.mu must be held.
+func (c *SoftComparator) emit(ev ComparatorEvent) {
+	for _, fn := range c.handlers {
+		fn(ev)
+	}
+	for _, ch := range c.chans {
+		select {
+		case ch <- ev:
+		default:
+			c.dropped++
+		}
+	}
+}
+
+// Watch samples input and feeds each reading to the comparator until ctx
+// is done. Conversions are paced as they are by Sample, and the config is
+// restored when Watch returns.
+func (adc *ADC) Watch(ctx context.Context, input AIN, c *SoftComparator) (err error) {
+	sp, err := adc.newSampler(input)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := sp.close(); err == nil {
+			err = cerr
+		}
+	}()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t, v, err := sp.next(ctx)
+		if err != nil {
+			return err
+		}
+		c.Update(t, v)
+	}
+}
@@ -0,0 +1,102 @@
+package ads111x
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_SoftComparator_Traditional(t *testing.T) {
+	c, err := NewSoftComparator(Traditional, Off, AfterTwo, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []ComparatorEvent
+	c.OnEvent(func(ev ComparatorEvent) { events = append(events, ev) })
+
+	// One reading above high isn't enough with AfterTwo, and readings
+	// between the thresholds don't deassert.
+	for _, v := range []float64{2.5, 1.5, 2.5, 2.5, 1.5, 0.5} {
+		c.Update(time.Time{}, v)
+	}
+	if len(events) != 2 {
+		t.Fatalf("exp = 2 events, got = %v", events)
+	}
+	if ev := events[0]; ev.Type != AlertEnter || !ev.High || ev.Volts != 2.5 {
+		t.Fatalf("unexpected enter event: %+v", ev)
+	}
+	if ev := events[1]; ev.Type != AlertExit || ev.Volts != 0.5 {
+		t.Fatalf("unexpected exit event: %+v", ev)
+	}
+}
+
+func Test_SoftComparator_Window(t *testing.T) {
+	c, err := NewSoftComparator(Window, Off, AfterOne, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := c.Events(1)
+
+	c.Update(time.Time{}, 0.5)
+	if ev := <-ch; ev.Type != AlertEnter || ev.High {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+	c.Update(time.Time{}, 1.5)
+	if ev := <-ch; ev.Type != AlertExit {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	// Events are dropped when the channel is full.
+	c.Update(time.Time{}, 3)
+	c.Update(time.Time{}, 1.5)
+	if c.Dropped() != 1 {
+		t.Fatalf("exp = 1 dropped, got = %d", c.Dropped())
+	}
+}
+
+func Test_SoftComparator_Latching(t *testing.T) {
+	c, err := NewSoftComparator(Traditional, On, AfterOne, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Update(time.Time{}, 3)
+	c.Update(time.Time{}, 0)
+	if !c.Asserted() {
+		t.Fatal("exp latched comparator to stay asserted")
+	}
+	c.Clear()
+	if c.Asserted() {
+		t.Fatal("exp comparator to be cleared")
+	}
+
+	c, _ = NewSoftComparator(Traditional, Off, Disable, 1, 2)
+	c.Update(time.Time{}, 3)
+	if c.Asserted() {
+		t.Fatal("exp disabled comparator to never assert")
+	}
+
+	if _, err := NewSoftComparator(Traditional, Off, AfterOne, 2, 1); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_Watch(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	adc := newSeqTestADC(0, 0x4000)
+	defer mustClose(adc)
+
+	c, err := NewSoftComparator(Traditional, Off, AfterOne, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.OnEvent(func(ev ComparatorEvent) { cancel() })
+	if err := adc.Watch(ctx, AIN_0_GND, c); err != context.Canceled {
+		t.Fatalf("exp = %v, got = %v", context.Canceled, err)
+	}
+	if !c.Asserted() {
+		t.Fatal("exp comparator to be asserted")
+	}
+}
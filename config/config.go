@@ -0,0 +1,606 @@
+// Package config loads a description of ADS111x devices and channels from a
+// YAML or JSON file, validates it and opens the devices it describes.
+//
+// An example file:
+//
+//	buses:
+//	  - device: /dev/i2c-1
+//	    adcs:
+//	      - address: 0x48
+//	        variant: ADS1115
+//	        supply: 3.3
+//	        data_rate: 128sps
+//	        channels:
+//	          - name: battery
+//	            input: ain0-gnd
+//	            scale: 2.048V
+//	            sensor:
+//	              type: divider
+//	              top: 100000
+//	              bottom: 10000
+//	          - name: tank
+//	            input: ain1-gnd
+//	            calibration: {gain: 1.002, offset: -0.001}
+//	            sensor:
+//	              - {type: resistance, supply: 3.3, series: 10000}
+//	              - {type: beta, r0: 10000, beta: 3950}
+//	      - address: 0x48
+//	        mux: {address: 0x70, channel: 5}
+//	        channels:
+//	          - {name: pressure, input: ain0-ain1}
+//
+// JSON files use the same keys.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/sensors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultSupply is the supply voltage assumed when an ADC doesn't set one.
+const DefaultSupply = 3.3
+
+// File is a description of ADCs on one or more buses.
+type File struct {
+	Buses []*BusSpec `yaml:"buses"`
+	pos
+}
+
+// BusSpec describes an I2C bus and the ADCs on it.
+type BusSpec struct {
+	// Device is the bus device, e.g., /dev/i2c-1.
+	Device string     `yaml:"device"`
+	ADCs   []*ADCSpec `yaml:"adcs"`
+	pos
+}
+
+// ADCSpec describes an ADC. The scale and data rate are defaults for its
+// channels.
+type ADCSpec struct {
+	Address  Address           `yaml:"address"`
+	Mux      *MuxSpec          `yaml:"mux"`
+	Variant  Variant           `yaml:"variant"`
+	Supply   float64           `yaml:"supply"`
+	Scale    *ads111x.Scale    `yaml:"scale"`
+	DataRate *ads111x.DataRate `yaml:"data_rate"`
+	Channels []*ChannelSpec    `yaml:"channels"`
+	pos
+}
+
+// MuxSpec places an ADC behind a TCA9548A mux.
+type MuxSpec struct {
+	Address Address `yaml:"address"`
+	Channel int     `yaml:"channel"`
+	pos
+}
+
+// ChannelSpec describes a named input. Unset scale and data rate default to
+// the ADC's.
+type ChannelSpec struct {
+	Name        string            `yaml:"name"`
+	Input       ads111x.AIN       `yaml:"input"`
+	Scale       *ads111x.Scale    `yaml:"scale"`
+	DataRate    *ads111x.DataRate `yaml:"data_rate"`
+	Calibration *Calibration      `yaml:"calibration"`
+	Sensor      Stages            `yaml:"sensor"`
+	pos
+}
+
+// Calibration corrects the volts read from a channel: Gain*v + Offset.
+type Calibration struct {
+	Gain   float64 `yaml:"gain"`
+	Offset float64 `yaml:"offset"`
+	pos
+}
+
+// Stages are a channel's transfer function, applied in order. In a file
+// it's a single stage or a list of them.
+type Stages []*StageSpec
+
+// StageSpec describes one transfer function stage. Type selects which of
+// the other fields are used:
+//
+//	divider         top, bottom
+//	shunt           ohms
+//	loop            ohms, min, max, unit
+//	linear          gain, offset, unit
+//	map             in_low, in_high, out_low, out_high, unit
+//	table           points, unit
+//	resistance      supply, series, high_side
+//	beta            r0, t0, beta
+//	steinhart-hart  a, b, c
+type StageSpec struct {
+	Type     string       `yaml:"type"`
+	Top      float64      `yaml:"top"`
+	Bottom   float64      `yaml:"bottom"`
+	Ohms     float64      `yaml:"ohms"`
+	Min      float64      `yaml:"min"`
+	Max      float64      `yaml:"max"`
+	Unit     string       `yaml:"unit"`
+	Gain     float64      `yaml:"gain"`
+	Offset   float64      `yaml:"offset"`
+	InLow    float64      `yaml:"in_low"`
+	InHigh   float64      `yaml:"in_high"`
+	OutLow   float64      `yaml:"out_low"`
+	OutHigh  float64      `yaml:"out_high"`
+	Points   [][2]float64 `yaml:"points"`
+	Supply   float64      `yaml:"supply"`
+	Series   float64      `yaml:"series"`
+	HighSide bool         `yaml:"high_side"`
+	R0       float64      `yaml:"r0"`
+	T0       float64      `yaml:"t0"`
+	Beta     float64      `yaml:"beta"`
+	A        float64      `yaml:"a"`
+	B        float64      `yaml:"b"`
+	C        float64      `yaml:"c"`
+	pos
+}
+
+// stageKeys are the required and optional keys of each stage type.
+var stageKeys = map[string]struct{ required, optional []string }{
+	"divider":        {[]string{"top", "bottom"}, nil},
+	"shunt":          {[]string{"ohms"}, nil},
+	"loop":           {[]string{"ohms", "min", "max"}, []string{"unit"}},
+	"linear":         {[]string{"gain"}, []string{"offset", "unit"}},
+	"map":            {[]string{"in_low", "in_high", "out_low", "out_high"}, []string{"unit"}},
+	"table":          {[]string{"points"}, []string{"unit"}},
+	"resistance":     {[]string{"supply", "series"}, []string{"high_side"}},
+	"beta":           {[]string{"r0", "beta"}, []string{"t0"}},
+	"steinhart-hart": {[]string{"a", "b", "c"}, nil},
+}
+
+// Address is an I2C address. In a file it's a number, e.g., 0x48 or 72, or
+// a string holding one.
+type Address uint8
+
+// UnmarshalText parses an address.
+func (a *Address) UnmarshalText(b []byte) error {
+	v, err := strconv.ParseUint(string(b), 0, 8)
+	if err != nil {
+		return fmt.Errorf("invalid address %q", b)
+	}
+	*a = Address(v)
+	return nil
+}
+
+// Variant is a member of the ADS111x family.
+type Variant string
+
+// The ADS111x variants. ADS1113 has a fixed +/- 2.048V scale, and neither
+// ADS1113 nor ADS1114 has an input multiplexer, so they only have AIN_0_1.
+const (
+	ADS1113 Variant = "ADS1113"
+	ADS1114 Variant = "ADS1114"
+	ADS1115 Variant = "ADS1115"
+)
+
+// UnmarshalText parses a variant, ignoring case.
+func (v *Variant) UnmarshalText(b []byte) error {
+	for _, vv := range []Variant{ADS1113, ADS1114, ADS1115} {
+		if strings.EqualFold(string(b), string(vv)) {
+			*v = vv
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid variant %q, must be one of: ADS1113, ADS1114, ADS1115", b)
+}
+
+// Error is an error at a line of a config file.
+type Error struct {
+	// File is the name of the file, if it was loaded from one.
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// Errors are every error found validating a file.
+type Errors []*Error
+
+func (es Errors) Error() string {
+	s := make([]string, len(es))
+	for i, e := range es {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+func errorf(line int, format string, a ...interface{}) *Error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, a...)}
+}
+
+// Load reads and validates a config file.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := Parse(data)
+	var e *Error
+	var es Errors
+	if errors.As(err, &es) {
+		for _, e := range es {
+			e.File = path
+		}
+	} else if errors.As(err, &e) {
+		e.File = path
+	}
+	return f, err
+}
+
+// yamlLine matches the line number in YAML syntax errors.
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// Parse parses and validates YAML or JSON. Errors are an *Error or, for
+// validation, Errors.
+func Parse(data []byte) (*File, error) {
+	if d := bytes.TrimSpace(data); len(d) > 0 && d[0] == '{' {
+		// Tabs can't indent YAML, but JSON is otherwise YAML, and tabs
+		// outside of JSON strings are only whitespace.
+		data = bytes.Replace(data, []byte("\t"), []byte(" "), -1)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			return nil, errorf(line, "%s", m[2])
+		}
+		return nil, errorf(1, "%s", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if len(doc.Content) == 0 {
+		return nil, errorf(1, "empty config")
+	}
+	f := &File{}
+	if err := doc.Content[0].Decode(f); err != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			return nil, e
+		}
+		return nil, errorf(doc.Content[0].Line, "%v", err)
+	}
+	if es := f.validate(); len(es) > 0 {
+		return nil, es
+	}
+	return f, nil
+}
+
+// pos is where a spec is in the file.
+type pos struct {
+	line int
+	// keys are the lines of the spec's keys.
+	keys map[string]int
+}
+
+// at returns the line of key, or of the spec if key isn't set.
+func (p pos) at(key string) int {
+	if l, ok := p.keys[key]; ok {
+		return l
+	}
+	return p.line
+}
+
+func (p pos) has(key string) bool {
+	_, ok := p.keys[key]
+	return ok
+}
+
+// decodeMapping decodes a mapping into the struct v points to, one key at a
+// time so errors have the line of the bad value, and returns its position.
+func decodeMapping(n *yaml.Node, v interface{}) (pos, error) {
+	p := pos{line: n.Line, keys: make(map[string]int)}
+	if n.Kind != yaml.MappingNode {
+		return p, errorf(n.Line, "expected a mapping")
+	}
+	rv := reflect.ValueOf(v).Elem()
+	fields := make(map[string]int)
+	for i := 0; i < rv.NumField(); i++ {
+		name := strings.Split(rv.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" {
+			fields[name] = i
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, val := n.Content[i], n.Content[i+1]
+		fi, ok := fields[k.Value]
+		if !ok {
+			return p, errorf(k.Line, "unknown key %q", k.Value)
+		}
+		if p.has(k.Value) {
+			return p, errorf(k.Line, "duplicate key %q", k.Value)
+		}
+		p.keys[k.Value] = k.Line
+		if err := val.Decode(rv.Field(fi).Addr().Interface()); err != nil {
+			var e *Error
+			if errors.As(err, &e) {
+				return p, e
+			}
+			var te *yaml.TypeError
+			if errors.As(err, &te) {
+				// Drop yaml's "line N: " prefixes; the line is in the Error.
+				msgs := make([]string, len(te.Errors))
+				for i, m := range te.Errors {
+					msgs[i] = m[strings.Index(m, ": ")+2:]
+				}
+				return p, errorf(val.Line, "%s: %s", k.Value, strings.Join(msgs, "; "))
+			}
+			return p, errorf(val.Line, "%s: %v", k.Value, err)
+		}
+	}
+	return p, nil
+}
+
+func (f *File) UnmarshalYAML(n *yaml.Node) (err error) {
+	f.pos, err = decodeMapping(n, f)
+	return err
+}
+
+func (b *BusSpec) UnmarshalYAML(n *yaml.Node) (err error) {
+	b.pos, err = decodeMapping(n, b)
+	return err
+}
+
+func (a *ADCSpec) UnmarshalYAML(n *yaml.Node) (err error) {
+	a.pos, err = decodeMapping(n, a)
+	return err
+}
+
+func (m *MuxSpec) UnmarshalYAML(n *yaml.Node) (err error) {
+	m.pos, err = decodeMapping(n, m)
+	return err
+}
+
+func (c *ChannelSpec) UnmarshalYAML(n *yaml.Node) (err error) {
+	c.pos, err = decodeMapping(n, c)
+	return err
+}
+
+func (c *Calibration) UnmarshalYAML(n *yaml.Node) (err error) {
+	c.pos, err = decodeMapping(n, c)
+	if !c.has("gain") {
+		c.Gain = 1
+	}
+	return err
+}
+
+func (s *StageSpec) UnmarshalYAML(n *yaml.Node) (err error) {
+	s.pos, err = decodeMapping(n, s)
+	if !s.has("t0") {
+		s.T0 = 25
+	}
+	return err
+}
+
+func (s *Stages) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.SequenceNode {
+		return n.Decode((*[]*StageSpec)(s))
+	}
+	st := &StageSpec{}
+	if err := n.Decode(st); err != nil {
+		return err
+	}
+	*s = Stages{st}
+	return nil
+}
+
+// validate returns every error in the file.
+func (f *File) validate() Errors {
+	var es Errors
+	add := func(e *Error) { es = append(es, e) }
+	if len(f.Buses) == 0 {
+		add(errorf(f.at("buses"), "no buses"))
+	}
+	devs := make(map[string]int)
+	names := make(map[string]int)
+	for _, b := range f.Buses {
+		if b.Device == "" {
+			add(errorf(b.line, "bus device is required"))
+		} else if l, ok := devs[b.Device]; ok {
+			add(errorf(b.at("device"), "duplicate bus %s, first at line %d", b.Device, l))
+		} else {
+			devs[b.Device] = b.at("device")
+		}
+		addrs := make(map[string]int)
+		for _, a := range b.ADCs {
+			a.validate(add)
+			key := fmt.Sprintf("0x%x", uint8(a.Address))
+			if a.Mux != nil {
+				key = fmt.Sprintf("0x%x on mux 0x%x channel %d", uint8(a.Address), uint8(a.Mux.Address), a.Mux.Channel)
+			}
+			if l, ok := addrs[key]; ok {
+				add(errorf(a.at("address"), "duplicate address %s on %s, first at line %d", key, b.Device, l))
+			} else {
+				addrs[key] = a.at("address")
+			}
+			for _, c := range a.Channels {
+				if c.Name == "" {
+					continue
+				}
+				if l, ok := names[c.Name]; ok {
+					add(errorf(c.at("name"), "duplicate channel name %q, first at line %d", c.Name, l))
+				} else {
+					names[c.Name] = c.at("name")
+				}
+			}
+		}
+	}
+	return es
+}
+
+func (a *ADCSpec) validate(add func(*Error)) {
+	if !a.has("address") {
+		add(errorf(a.line, "ADC address is required"))
+	} else if ads111x.I2CAddress(a.Address) < ads111x.Addr48 || a.Address > ads111x.Addr4B {
+		add(errorf(a.at("address"), "invalid ADC address 0x%x, must be 0x48 through 0x4b", uint8(a.Address)))
+	}
+	if m := a.Mux; m != nil {
+		if m.Address < ads111x.DefaultMuxAddr || m.Address > ads111x.DefaultMuxAddr+7 {
+			add(errorf(m.at("address"), "invalid mux address 0x%x, must be 0x70 through 0x77", uint8(m.Address)))
+		}
+		if m.Channel < 0 || m.Channel >= ads111x.MuxChannels {
+			add(errorf(m.at("channel"), "invalid mux channel %d, must be 0 through 7", m.Channel))
+		}
+	}
+	if a.Variant == "" {
+		a.Variant = ADS1115
+	}
+	if !a.has("supply") {
+		a.Supply = DefaultSupply
+	} else if a.Supply < 2 || a.Supply > 5.5 {
+		add(errorf(a.at("supply"), "supply %gV outside of 2V to 5.5V", a.Supply))
+	}
+	if a.Scale != nil {
+		a.validateScale(*a.Scale, a.at("scale"), add)
+	}
+	if len(a.Channels) == 0 {
+		add(errorf(a.line, "ADC 0x%x has no channels", uint8(a.Address)))
+	}
+	for _, c := range a.Channels {
+		c.validate(a, add)
+	}
+}
+
+// validateScale checks that the ADC supports the scale and that the scale
+// doesn't exceed its supply.
+func (a *ADCSpec) validateScale(fs ads111x.Scale, line int, add func(*Error)) {
+	if _, err := fs.MarshalText(); err != nil {
+		add(errorf(line, "%v", err))
+		return
+	}
+	if a.Variant == ADS1113 && fs != ads111x.Scale_2_048V {
+		add(errorf(line, "ADS1113 only supports a 2.048V scale"))
+	}
+	if _, max := ads111x.ScaleMinMax(fs); max > a.Supply {
+		add(errorf(line, "scale %.3fV exceeds supply %gV", max, a.Supply))
+	}
+}
+
+func (c *ChannelSpec) validate(a *ADCSpec, add func(*Error)) {
+	if c.Name == "" {
+		add(errorf(c.line, "channel name is required"))
+	}
+	if !c.has("input") {
+		add(errorf(c.line, "channel input is required"))
+	} else if a.Variant != ADS1115 && c.Input != ads111x.AIN_0_1 {
+		add(errorf(c.at("input"), "%s only supports input ain0-ain1", a.Variant))
+	}
+	if c.Scale != nil {
+		a.validateScale(*c.Scale, c.at("scale"), add)
+	} else if a.Scale == nil {
+		// The device's default scale.
+		a.validateScale(ads111x.Scale_2_048V, c.line, add)
+	}
+	if cal := c.Calibration; cal != nil && cal.Gain == 0 {
+		add(errorf(cal.at("gain"), "calibration gain must not be 0"))
+	}
+	for _, s := range c.Sensor {
+		if _, err := s.converter(); err != nil {
+			add(err.(*Error))
+		}
+	}
+}
+
+// converter validates the stage and returns its converter.
+func (s *StageSpec) converter() (sensors.Converter, error) {
+	keys, ok := stageKeys[s.Type]
+	if !ok {
+		var types []string
+		for t := range stageKeys {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		return nil, errorf(s.at("type"), "invalid sensor type %q, must be one of: %s", s.Type, strings.Join(types, ", "))
+	}
+	allowed := map[string]bool{"type": true}
+	for _, k := range append(keys.required, keys.optional...) {
+		allowed[k] = true
+	}
+	for k, l := range s.keys {
+		if !allowed[k] {
+			return nil, errorf(l, "%s sensor doesn't use %s", s.Type, k)
+		}
+	}
+	for _, k := range keys.required {
+		if !s.has(k) {
+			return nil, errorf(s.line, "%s sensor requires %s", s.Type, k)
+		}
+	}
+
+	positive := func(key string, v float64) error {
+		if v <= 0 {
+			return errorf(s.at(key), "%s must be positive", key)
+		}
+		return nil
+	}
+	switch s.Type {
+	case "divider":
+		if err := positive("bottom", s.Bottom); err != nil {
+			return nil, err
+		}
+		if s.Top < 0 {
+			return nil, errorf(s.at("top"), "top must not be negative")
+		}
+		return sensors.Divider{Top: s.Top, Bottom: s.Bottom}, nil
+	case "shunt":
+		if err := positive("ohms", s.Ohms); err != nil {
+			return nil, err
+		}
+		return sensors.Shunt{Ohms: s.Ohms}, nil
+	case "loop":
+		if err := positive("ohms", s.Ohms); err != nil {
+			return nil, err
+		}
+		return sensors.Loop{Ohms: s.Ohms, Min: s.Min, Max: s.Max, Units: s.Unit}, nil
+	case "linear":
+		return sensors.Linear{Gain: s.Gain, Offset: s.Offset, Units: s.Unit}, nil
+	case "map":
+		if s.InLow == s.InHigh {
+			return nil, errorf(s.at("in_high"), "in_low and in_high must differ")
+		}
+		return sensors.Map(s.InLow, s.InHigh, s.OutLow, s.OutHigh, s.Unit), nil
+	case "table":
+		pts := make([]sensors.Point, len(s.Points))
+		for i, p := range s.Points {
+			pts[i] = sensors.Point{In: p[0], Out: p[1]}
+		}
+		t, err := sensors.NewTable(s.Unit, pts...)
+		if err != nil {
+			return nil, errorf(s.at("points"), "%v", err)
+		}
+		return t, nil
+	case "resistance":
+		if err := positive("supply", s.Supply); err != nil {
+			return nil, err
+		}
+		if err := positive("series", s.Series); err != nil {
+			return nil, err
+		}
+		return sensors.Resistance{Supply: s.Supply, Series: s.Series, HighSide: s.HighSide}, nil
+	case "beta":
+		if err := positive("r0", s.R0); err != nil {
+			return nil, err
+		}
+		if err := positive("beta", s.Beta); err != nil {
+			return nil, err
+		}
+		return sensors.Beta{R0: s.R0, T0: s.T0, B: s.Beta}, nil
+	default: // steinhart-hart
+		return sensors.SteinhartHart{A: s.A, B: s.B, C: s.C}, nil
+	}
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+)
+
+const testYAML = `
+buses:
+  - device: /dev/i2c-1
+    adcs:
+      - address: 0x48
+        supply: 5
+        data_rate: 860sps
+        channels:
+          - name: battery
+            input: ain0-gnd
+            scale: 4.096V
+            sensor: {type: divider, top: 30000, bottom: 10000}
+          - name: tank
+            input: ain1-gnd
+            calibration: {offset: 0.01}
+            sensor:
+              - {type: resistance, supply: 3.3, series: 10000}
+              - {type: beta, r0: 10000, beta: 3950}
+      - address: "0x49"
+        variant: ads1114
+        mux: {address: 0x70, channel: 5}
+        channels:
+          - {name: bridge, input: ain0-ain1, scale: 0.256V}
+`
+
+func Test_Parse(t *testing.T) {
+	f, err := Parse([]byte(testYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := f.Buses[0].ADCs[0]
+	if a.Address != 0x48 || a.Variant != ADS1115 || a.Supply != 5 || *a.DataRate != ads111x.DR_860SPS {
+		t.Fatalf("unexpected ADC: %+v", a)
+	}
+	c := a.Channels[1]
+	if c.Input != ads111x.AIN_1_GND || c.Calibration.Gain != 1 || len(c.Sensor) != 2 || c.Sensor[1].T0 != 25 {
+		t.Fatalf("unexpected channel: %+v", c)
+	}
+	b := f.Buses[0].ADCs[1]
+	if b.Address != 0x49 || b.Variant != ADS1114 || b.Supply != DefaultSupply || b.Mux.Channel != 5 {
+		t.Fatalf("unexpected ADC: %+v", b)
+	}
+}
+
+func Test_ParseJSON(t *testing.T) {
+	js := "{\n\t\"buses\": [{\n\t\t\"device\": \"/dev/i2c-1\",\n\t\t\"adcs\": [{\n\t\t\t\"address\": 72,\n" +
+		"\t\t\t\"channels\": [{\"name\": \"a\", \"input\": \"AIN_0_3\", \"scale\": \"6.144V\"}]\n\t\t}]\n\t}]\n}\n"
+	_, err := Parse([]byte(js))
+	var es Errors
+	if !errors.As(err, &es) || len(es) != 1 {
+		t.Fatalf("exp = 1 error, got = %v", err)
+	}
+	// 6.144V exceeds the default supply.
+	if exp := "line 6: scale 6.144V exceeds supply 3.3V"; es[0].Error() != exp {
+		t.Fatalf("exp = %s, got = %s", exp, es[0])
+	}
+}
+
+func Test_ParseErrors(t *testing.T) {
+	test := func(exp, doc string) {
+		t.Helper()
+		_, err := Parse([]byte(doc))
+		if err == nil {
+			t.Fatalf("expected error: %s", exp)
+		} else if !strings.Contains(err.Error(), exp) {
+			t.Fatalf("exp = %s, got = %s", exp, err)
+		}
+	}
+	test("line 1: empty config", "")
+	test("line 3: mapping values are not allowed", "buses:\n  - device: d\n    adcs: x: y\n")
+	test("line 1: unknown key \"bus\"", "bus: []")
+	test("line 1: no buses", "buses: []")
+	test("line 4: input: invalid AIN \"ain9\"",
+		"buses:\n- device: d\n  adcs:\n  - {address: 0x48, channels: [{name: a, input: ain9}]}")
+	test("line 5: supply: cannot unmarshal !!str `high` into float64",
+		"buses:\n- device: d\n  adcs:\n  - address: 0x48\n    supply: high")
+
+	dup := `buses:
+- device: d
+  adcs:
+  - address: 0x48
+    channels: [{name: a, input: ain0}]
+  - address: 0x48
+    channels: [{name: a, input: ain1}]
+  - address: 0x4c
+    variant: ADS1113
+    channels:
+    - {name: b, input: ain2}
+    - {name: c, input: ain0-ain1, scale: 1.024V}
+`
+	_, err := Parse([]byte(dup))
+	var es Errors
+	if !errors.As(err, &es) {
+		t.Fatalf("exp = Errors, got = %v", err)
+	}
+	exp := []string{
+		"line 6: duplicate address 0x48 on d, first at line 4",
+		"line 7: duplicate channel name \"a\", first at line 5",
+		"line 8: invalid ADC address 0x4c, must be 0x48 through 0x4b",
+		"line 11: ADS1113 only supports input ain0-ain1",
+		"line 12: ADS1113 only supports a 2.048V scale",
+	}
+	if len(es) != len(exp) {
+		t.Fatalf("exp = %d errors, got = %v", len(exp), err)
+	}
+	for i := range exp {
+		if es[i].Error() != exp[i] {
+			t.Fatalf("exp = %s, got = %s", exp[i], es[i])
+		}
+	}
+
+	sensor := func(s string) string {
+		return "buses:\n- device: d\n  adcs:\n  - address: 0x48\n    channels:\n    - name: a\n      input: ain0\n      sensor: " + s
+	}
+	test("line 8: invalid sensor type \"thermo\", must be one of: beta, divider", sensor("{type: thermo}"))
+	test("line 8: divider sensor requires bottom", sensor("{type: divider, top: 1}"))
+	test("line 10: divider sensor doesn't use ohms", sensor("\n        type: divider\n        ohms: 1"))
+	test("line 8: bottom must be positive", sensor("{type: divider, top: 1, bottom: 0}"))
+	test("line 8: table needs at least 2 points", sensor("{type: table, points: [[0, 1]]}"))
+}
+
+func Test_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ads.yaml")
+	if err := os.WriteFile(path, []byte("buses:\n- device: d\n  adcs: [{address: 0x50}]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.HasPrefix(err.Error(), path+":3: invalid ADC address 0x50") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
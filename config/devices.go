@@ -0,0 +1,251 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/sensors"
+)
+
+// openBus is for test purposes.
+var openBus = ads111x.OpenBus
+
+// Devices are the buses, ADCs and channels opened from a file.
+type Devices struct {
+	ADCs     []*ADC
+	Channels []*Channel
+	buses    []*ads111x.Bus
+	muxes    []*ads111x.Mux
+	byDev    map[string]*ads111x.Bus
+	byName   map[string]*Channel
+}
+
+// ADC is an opened ADC. Its channels lock it while they configure and read
+// it.
+type ADC struct {
+	*ads111x.ADC
+	Spec *ADCSpec
+	// Bus is the bus device the ADC is on.
+	Bus string
+	mu  sync.Mutex
+}
+
+// Channel is a named input with its scale, data rate, calibration and
+// transfer function. It's safe for concurrent use.
+type Channel struct {
+	Name     string
+	ADC      *ADC
+	Input    ads111x.AIN
+	Scale    ads111x.Scale
+	DataRate ads111x.DataRate
+	// Gain and Offset are the calibration.
+	Gain   float64
+	Offset float64
+	sensor *sensors.Sensor
+}
+
+// Open loads a config file and opens the devices it describes.
+func Open(path string) (*Devices, error) {
+	f, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.Open()
+}
+
+// Open opens the buses and ADCs and builds the channels.
+func (f *File) Open() (*Devices, error) {
+	return f.OpenWith(openBus)
+}
+
+// OpenWith is like Open, but opens the buses with open.
+func (f *File) OpenWith(open func(dev string) (*ads111x.Bus, error)) (*Devices, error) {
+	d := &Devices{byName: make(map[string]*Channel), byDev: make(map[string]*ads111x.Bus)}
+	for _, bs := range f.Buses {
+		bus, err := open(bs.Device)
+		if err != nil {
+			d.Close()
+			return nil, err
+		}
+		d.buses = append(d.buses, bus)
+		d.byDev[bs.Device] = bus
+		muxes := make(map[Address]*ads111x.Mux)
+		for _, as := range bs.ADCs {
+			adc, err := d.openADC(bus, muxes, as)
+			if err != nil {
+				d.Close()
+				return nil, fmt.Errorf("%s: ADC 0x%x: %v", bs.Device, uint8(as.Address), err)
+			}
+			a := &ADC{ADC: adc, Spec: as, Bus: bs.Device}
+			d.ADCs = append(d.ADCs, a)
+			for _, cs := range as.Channels {
+				c := newChannel(a, cs)
+				d.Channels = append(d.Channels, c)
+				d.byName[c.Name] = c
+			}
+		}
+	}
+	return d, nil
+}
+
+// openADC opens the ADC, and its mux if it's the first ADC behind it.
+func (d *Devices) openADC(bus *ads111x.Bus, muxes map[Address]*ads111x.Mux, as *ADCSpec) (*ads111x.ADC, error) {
+	if as.Mux == nil {
+		return bus.OpenADC(ads111x.I2CAddress(as.Address))
+	}
+	mux, ok := muxes[as.Mux.Address]
+	if !ok {
+		var err error
+		if mux, err = ads111x.NewMux(bus, int(as.Mux.Address)); err != nil {
+			return nil, err
+		}
+		muxes[as.Mux.Address] = mux
+		d.muxes = append(d.muxes, mux)
+	}
+	cb, err := mux.Channel(as.Mux.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return cb.OpenADC(ads111x.I2CAddress(as.Address))
+}
+
+func newChannel(a *ADC, cs *ChannelSpec) *Channel {
+	c := &Channel{
+		Name:     cs.Name,
+		ADC:      a,
+		Input:    cs.Input,
+		Scale:    ads111x.Scale_2_048V,
+		DataRate: ads111x.DR_128SPS,
+		Gain:     1,
+	}
+	switch {
+	case cs.Scale != nil:
+		c.Scale = *cs.Scale
+	case a.Spec.Scale != nil:
+		c.Scale = *a.Spec.Scale
+	}
+	switch {
+	case cs.DataRate != nil:
+		c.DataRate = *cs.DataRate
+	case a.Spec.DataRate != nil:
+		c.DataRate = *a.Spec.DataRate
+	}
+	if cal := cs.Calibration; cal != nil {
+		c.Gain, c.Offset = cal.Gain, cal.Offset
+	}
+	var conv []sensors.Converter
+	for _, s := range cs.Sensor {
+		// The file was validated, so the stages are too.
+		cv, _ := s.converter()
+		conv = append(conv, cv)
+	}
+	c.sensor = sensors.New(channelReader{c}, c.Input, conv...)
+	return c
+}
+
+// Channel returns the channel with the name.
+func (d *Devices) Channel(name string) (*Channel, bool) {
+	c, ok := d.byName[name]
+	return c, ok
+}
+
+// Bus returns the opened bus device, e.g., /dev/i2c-1.
+func (d *Devices) Bus(dev string) (*ads111x.Bus, bool) {
+	b, ok := d.byDev[dev]
+	return b, ok
+}
+
+// Close closes the ADCs, muxes and buses.
+func (d *Devices) Close() error {
+	var first error
+	for _, a := range d.ADCs {
+		if err := a.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, m := range d.muxes {
+		if err := m.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, b := range d.buses {
+		if err := b.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// ReadVolts sets the ADC's scale and data rate for the channel, if needed,
+// and returns the calibrated volts.
+func (c *Channel) ReadVolts() (float64, error) {
+	_, v, err := c.read()
+	return v, err
+}
+
+// Sample is a channel reading with its raw conversion.
+type Sample struct {
+	sensors.Reading
+	Raw int16
+	// Clipped is true when the conversion is at either end of the scale, so
+	// the input may be beyond it.
+	Clipped bool
+}
+
+// Sample reads the channel once and returns the raw conversion, the
+// calibrated volts and the value from its transfer function.
+func (c *Channel) Sample() (Sample, error) {
+	raw, v, err := c.read()
+	if err != nil {
+		return Sample{}, err
+	}
+	r, err := c.sensor.Convert(v)
+	if err != nil {
+		return Sample{}, err
+	}
+	return Sample{
+		Reading: r,
+		Raw:     raw,
+		Clipped: raw == math.MaxInt16 || raw == math.MinInt16,
+	}, nil
+}
+
+// read returns the raw conversion and calibrated volts. The conversion is
+// made with the channel's input, scale and data rate, so it's never one
+// left over from another channel sharing the ADC.
+func (c *Channel) read() (int16, float64, error) {
+	a := c.ADC
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	cfg, err := a.Config()
+	if err != nil {
+		return 0, 0, err
+	}
+	mask := ads111x.AIN_Mask | ads111x.Scale_Mask | ads111x.DataRate_Mask
+	cnt, err := a.ConvertFrom(cfg, cfg&^mask|uint16(c.Input)|uint16(c.Scale)|uint16(c.DataRate))
+	if err != nil {
+		return 0, 0, err
+	}
+	return int16(cnt), c.Gain*ads111x.Volts(cnt, c.Scale) + c.Offset, nil
+}
+
+// Read reads the channel and applies its transfer function.
+func (c *Channel) Read() (sensors.Reading, error) {
+	return c.sensor.Read()
+}
+
+// Unit returns the label of the unit Read reports.
+func (c *Channel) Unit() string {
+	return c.sensor.Unit()
+}
+
+// channelReader reads a channel for its sensor.
+type channelReader struct {
+	c *Channel
+}
+
+func (r channelReader) ReadVolts(ads111x.AIN) (float64, error) {
+	return r.c.ReadVolts()
+}
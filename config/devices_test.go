@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Open(t *testing.T) {
+	// The ADC at 0x49 is behind the mux, on channel 5.
+	fb := fakedev.NewBus(0x48)
+	mux := fb.AddMux(ads111x.DefaultMuxAddr)
+	mux.SetChannel(5, fakedev.NewBus(0x49))
+	defer func() { openBus = ads111x.OpenBus }()
+	openBus = func(dev string) (*ads111x.Bus, error) {
+		if dev != "/dev/i2c-1" {
+			return nil, fmt.Errorf("no bus %s", dev)
+		}
+		return ads111x.NewBus(fb), nil
+	}
+
+	f, err := Parse([]byte(testYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := f.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(d.ADCs) != 2 || len(d.Channels) != 3 {
+		t.Fatalf("exp = 2 ADCs and 3 channels, got = %d and %d", len(d.ADCs), len(d.Channels))
+	}
+
+	// 1V at the bottom of a 3:1 divider, and the tank sensor. The channels
+	// have different scales, so each conversion must be made after its own
+	// input and scale are written.
+	dev := fb.Device(0x48)
+	dev.SetVolts(uint16(ads111x.AIN_0_GND), 1)
+	dev.SetVolts(uint16(ads111x.AIN_1_GND), 1.014)
+	c, ok := d.Channel("battery")
+	if !ok {
+		t.Fatal("battery not found")
+	}
+	r, err := c.Read()
+	if err != nil {
+		t.Fatal(err)
+	} else if r.Volts != 1 || r.Value != 4 || r.Unit != "V" {
+		t.Fatalf("unexpected reading: %+v", r)
+	}
+	cfg := dev.Reg(ads111x.ConfigReg)
+	if ads111x.Scale(cfg&ads111x.Scale_Mask) != ads111x.Scale_4_096V || ads111x.DataRate(cfg&ads111x.DataRate_Mask) != ads111x.DR_860SPS {
+		t.Fatalf("unexpected config: 0x%x", cfg)
+	}
+
+	// The tank channel uses the default scale, and its offset.
+	c, _ = d.Channel("tank")
+	r, err = c.Read()
+	if err != nil {
+		t.Fatal(err)
+	} else if math.Abs(r.Volts-1.024) > 1e-9 || r.Unit != "°C" {
+		t.Fatalf("unexpected reading: %+v", r)
+	}
+	if cfg := dev.Reg(ads111x.ConfigReg); ads111x.Scale(cfg&ads111x.Scale_Mask) != ads111x.Scale_2_048V {
+		t.Fatalf("unexpected config: 0x%x", cfg)
+	}
+
+	c, _ = d.Channel("battery")
+	if r, err = c.Read(); err != nil {
+		t.Fatal(err)
+	} else if r.Volts != 1 {
+		t.Fatalf("exp = 1, got = %v", r.Volts)
+	}
+	dev.SetVolts(uint16(ads111x.AIN_0_GND), -5)
+	if s, err := c.Sample(); err != nil {
+		t.Fatal(err)
+	} else if s.Raw != -32768 || !s.Clipped || s.Volts != -4.096 || s.Value != -16.384 {
+		t.Fatalf("unexpected sample: %+v", s)
+	}
+	if b, ok := d.Bus("/dev/i2c-1"); !ok || b == nil {
+		t.Fatal("bus not found")
+	}
+
+	// The bridge is behind the mux.
+	c, _ = d.Channel("bridge")
+	if _, err := c.ReadVolts(); err != nil {
+		t.Fatal(err)
+	} else if sel := mux.Selected(); sel != 1<<5 {
+		t.Fatalf("exp = 0x20, got = 0x%x", sel)
+	}
+
+	// Closing closes the mux too.
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	} else if !fb.Closed(ads111x.DefaultMuxAddr) {
+		t.Fatal("exp mux to be closed")
+	}
+
+	f.Buses[0].Device = "/dev/i2c-9"
+	if _, err := f.Open(); err == nil {
+		t.Fatal("expected error")
+	}
+}
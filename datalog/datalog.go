@@ -0,0 +1,544 @@
+// Package datalog records readings to CSV or JSON Lines files, with size
+// and time based rotation, optional gzip of rotated files and a choice of
+// fsync policies.
+package datalog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// now is for test purposes.
+var now = time.Now
+
+// ErrClosed is returned by writes to a closed Logger.
+var ErrClosed = errors.New("logger closed")
+
+// Record is one logged reading.
+type Record struct {
+	Time    time.Time
+	Channel string
+	Raw     int16
+	Volts   float64
+	// Value and Unit are the reading in engineering units.
+	Value float64
+	Unit  string
+	// Clipped is true when the conversion is at the end of the scale, so
+	// the input may be beyond it.
+	Clipped bool
+}
+
+// FromReading returns a record for a reading from an ads111x.Manager. Its
+// value is the volts.
+func FromReading(r ads111x.Reading) Record {
+	return Record{
+		Time:    r.Time,
+		Channel: r.Name,
+		Raw:     r.Raw,
+		Volts:   r.Volts,
+		Value:   r.Volts,
+		Unit:    "V",
+		Clipped: Clipped(r.Raw),
+	}
+}
+
+// Clipped returns whether a conversion is at either end of the scale.
+func Clipped(raw int16) bool {
+	return raw == math.MaxInt16 || raw == math.MinInt16
+}
+
+// Column is a field of a record.
+type Column string
+
+// The columns.
+const (
+	ColTime    Column = "time"
+	ColChannel Column = "channel"
+	ColRaw     Column = "raw"
+	ColVolts   Column = "volts"
+	ColValue   Column = "value"
+	ColUnit    Column = "unit"
+	ColClipped Column = "clipped"
+)
+
+// DefaultColumns are every column.
+var DefaultColumns = []Column{ColTime, ColChannel, ColRaw, ColVolts, ColValue, ColUnit, ColClipped}
+
+// ParseColumns parses a comma separated list of columns.
+func ParseColumns(s string) ([]Column, error) {
+	var cols []Column
+	for _, name := range strings.Split(s, ",") {
+		c := Column(strings.TrimSpace(strings.ToLower(name)))
+		found := false
+		for _, dc := range DefaultColumns {
+			found = found || c == dc
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// Format is a file format.
+type Format string
+
+// The formats.
+const (
+	CSV   Format = "csv"
+	JSONL Format = "jsonl"
+)
+
+// SyncPolicy is when a logger fsyncs its file.
+type SyncPolicy string
+
+// The sync policies.
+const (
+	// SyncNever leaves flushing to disk to the OS.
+	SyncNever SyncPolicy = "never"
+	// SyncRotate syncs before a file is rotated or closed.
+	SyncRotate SyncPolicy = "rotate"
+	// SyncInterval syncs after a write at most once per Options.SyncInterval,
+	// and before a file is rotated or closed.
+	SyncInterval SyncPolicy = "interval"
+	// SyncAlways syncs after every write.
+	SyncAlways SyncPolicy = "always"
+)
+
+// Options configure a Logger. The zero value writes every column to a CSV
+// file that is never rotated, and syncs on close.
+type Options struct {
+	Format  Format
+	Columns []Column
+	// MaxSize rotates the file once it's at least this many bytes.
+	MaxSize int64
+	// MaxAge rotates the file once it's been open this long.
+	MaxAge time.Duration
+	// Gzip compresses rotated files.
+	Gzip bool
+	// MaxFiles is how many rotated files to keep, or 0 to keep them all.
+	MaxFiles     int
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+}
+
+// Logger writes records to a file, rotating it to a timestamped name, e.g.,
+// log.csv to log-20060102T150405Z.csv. It isn't safe for concurrent use.
+type Logger struct {
+	path string
+	opts Options
+	// f is nil once the file is closed, and err is why: ErrClosed, or the
+	// error opening a new file when rotating.
+	f      *os.File
+	w      *bufio.Writer
+	err    error
+	size   int64
+	opened time.Time
+	synced time.Time
+}
+
+// New opens or creates the file at path and appends to it.
+func New(path string, opts Options) (*Logger, error) {
+	if opts.Format == "" {
+		opts.Format = CSV
+	}
+	if opts.Format != CSV && opts.Format != JSONL {
+		return nil, fmt.Errorf("unknown format %q", opts.Format)
+	}
+	if len(opts.Columns) == 0 {
+		opts.Columns = DefaultColumns
+	}
+	switch opts.Sync {
+	case "":
+		opts.Sync = SyncRotate
+	case SyncNever, SyncRotate, SyncAlways:
+	case SyncInterval:
+		if opts.SyncInterval <= 0 {
+			return nil, fmt.Errorf("invalid sync interval %v", opts.SyncInterval)
+		}
+	default:
+		return nil, fmt.Errorf("unknown sync policy %q", opts.Sync)
+	}
+	l := &Logger{path: path, opts: opts}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.f, l.w, l.size = f, bufio.NewWriter(f), fi.Size()
+	l.opened, l.synced = now(), now()
+	if l.size == 0 && l.opts.Format == CSV {
+		hdr := make([]string, len(l.opts.Columns))
+		for i, c := range l.opts.Columns {
+			hdr[i] = string(c)
+		}
+		if err := l.writeCSV(hdr); err != nil {
+			return err
+		}
+		return l.flush()
+	}
+	return nil
+}
+
+// Write appends the records, rotating the file first if it's due. If
+// rotating couldn't open a new file, Write returns the error it failed with.
+func (l *Logger) Write(recs ...Record) error {
+	if l.f == nil {
+		return l.err
+	}
+	if l.due() {
+		if err := l.Rotate(); err != nil {
+			return err
+		}
+	}
+	for _, r := range recs {
+		var err error
+		if l.opts.Format == CSV {
+			err = l.writeCSV(l.csvRow(r))
+		} else {
+			err = l.writeJSON(r)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := l.flush(); err != nil {
+		return err
+	}
+	switch l.opts.Sync {
+	case SyncAlways:
+		return l.sync()
+	case SyncInterval:
+		if now().Sub(l.synced) >= l.opts.SyncInterval {
+			return l.sync()
+		}
+	}
+	return nil
+}
+
+// due returns whether the file should be rotated.
+func (l *Logger) due() bool {
+	if l.opts.MaxSize > 0 && l.size >= l.opts.MaxSize {
+		return true
+	}
+	return l.opts.MaxAge > 0 && now().Sub(l.opened) >= l.opts.MaxAge
+}
+
+func (l *Logger) csvRow(r Record) []string {
+	row := make([]string, len(l.opts.Columns))
+	for i, c := range l.opts.Columns {
+		switch c {
+		case ColTime:
+			row[i] = r.Time.UTC().Format(time.RFC3339Nano)
+		case ColChannel:
+			row[i] = r.Channel
+		case ColRaw:
+			row[i] = strconv.Itoa(int(r.Raw))
+		case ColVolts:
+			row[i] = formatFloat(r.Volts)
+		case ColValue:
+			row[i] = formatFloat(r.Value)
+		case ColUnit:
+			row[i] = r.Unit
+		case ColClipped:
+			row[i] = strconv.FormatBool(r.Clipped)
+		}
+	}
+	return row
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (l *Logger) writeCSV(row []string) error {
+	cw := csv.NewWriter(countWriter{l})
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (l *Logger) writeJSON(r Record) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, c := range l.opts.Columns {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		var v interface{}
+		switch c {
+		case ColTime:
+			v = r.Time.UTC().Format(time.RFC3339Nano)
+		case ColChannel:
+			v = r.Channel
+		case ColRaw:
+			v = r.Raw
+		case ColVolts:
+			v = jsonFloat(r.Volts)
+		case ColValue:
+			v = jsonFloat(r.Value)
+		case ColUnit:
+			v = r.Unit
+		case ColClipped:
+			v = r.Clipped
+		}
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%q:%s", c, val)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(countWriter{l}, b.String())
+	return err
+}
+
+// jsonFloat returns v, or nil for values JSON can't hold.
+func jsonFloat(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return v
+}
+
+// countWriter writes to the logger's buffer and counts the bytes.
+type countWriter struct {
+	l *Logger
+}
+
+func (w countWriter) Write(p []byte) (int, error) {
+	n, err := w.l.w.Write(p)
+	w.l.size += int64(n)
+	return n, err
+}
+
+func (l *Logger) flush() error {
+	return l.w.Flush()
+}
+
+func (l *Logger) sync() error {
+	l.synced = now()
+	return l.f.Sync()
+}
+
+// closeFile flushes and closes the current file, syncing it unless the
+// policy is SyncNever, and sets it to nil.
+func (l *Logger) closeFile() error {
+	if l.f == nil {
+		return nil
+	}
+	err := l.flush()
+	if l.opts.Sync != SyncNever {
+		if serr := l.f.Sync(); err == nil {
+			err = serr
+		}
+	}
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f, l.w = nil, nil
+	return err
+}
+
+// Rotate closes the file, renames it with the time and opens a new one.
+// The new file is opened before the rotated one is compressed and old ones
+// are pruned, so logging continues if either fails; the first error is
+// returned. If the new file can't be opened, the logger is left closed and
+// writes return the error.
+func (l *Logger) Rotate() error {
+	if l.f == nil {
+		return l.err
+	}
+	err := l.closeFile()
+	name := l.rotatedName()
+	renamed := os.Rename(l.path, name)
+	if oerr := l.open(); oerr != nil {
+		l.err = oerr
+		return oerr
+	}
+	if renamed != nil {
+		if err == nil {
+			err = renamed
+		}
+		return err
+	}
+	if l.opts.Gzip {
+		if cerr := l.compress(name); err == nil {
+			err = cerr
+		}
+	}
+	if perr := l.prune(); err == nil {
+		err = perr
+	}
+	return err
+}
+
+// rotatedLayout is the time layout in rotated file names.
+const rotatedLayout = "20060102T150405Z"
+
+// rotatedName returns an unused name for the current file.
+func (l *Logger) rotatedName() string {
+	ext := filepath.Ext(l.path)
+	base := strings.TrimSuffix(l.path, ext) + "-" + now().UTC().Format(rotatedLayout)
+	for i := 1; ; i++ {
+		name := base + ext
+		if i > 1 {
+			// Sorts after base+ext.
+			name = fmt.Sprintf("%s_%d%s", base, i, ext)
+		}
+		if !exists(name) && !exists(name+".gz") {
+			return name
+		}
+	}
+}
+
+func exists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
+
+// compress gzips name to name.gz and removes name.
+func (l *Logger) compress(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if l.opts.Sync != SyncNever && err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(name + ".gz")
+		return err
+	}
+	return os.Remove(name)
+}
+
+// prune removes the oldest rotated files beyond MaxFiles.
+func (l *Logger) prune() error {
+	if l.opts.MaxFiles <= 0 {
+		return nil
+	}
+	names, err := l.Rotated()
+	if err != nil {
+		return err
+	}
+	for len(names) > l.opts.MaxFiles {
+		if err := os.Remove(names[0]); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// Rotated returns the paths of the rotated files, oldest first.
+func (l *Logger) Rotated() ([]string, error) {
+	dir, file := filepath.Split(l.path)
+	ext := filepath.Ext(file)
+	prefix := strings.TrimSuffix(file, ext) + "-"
+	if dir == "" {
+		dir = "."
+	}
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	type rotated struct {
+		name string
+		t    time.Time
+		n    int
+	}
+	var rs []rotated
+	for _, de := range des {
+		if t, n, ok := parseRotated(de.Name(), prefix, ext); ok {
+			rs = append(rs, rotated{filepath.Join(dir, de.Name()), t, n})
+		}
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if !rs[i].t.Equal(rs[j].t) {
+			return rs[i].t.Before(rs[j].t)
+		}
+		return rs[i].n < rs[j].n
+	})
+	names := make([]string, len(rs))
+	for i, r := range rs {
+		names[i] = r.name
+	}
+	return names, nil
+}
+
+// parseRotated returns the time and sequence number of a name made by
+// rotatedName, e.g., log-20261019T000000Z_2.csv.gz, or false if it isn't
+// one.
+func parseRotated(name, prefix, ext string) (time.Time, int, bool) {
+	s := strings.TrimSuffix(name, ".gz")
+	if !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, ext) {
+		return time.Time{}, 0, false
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, prefix), ext)
+	if len(s) < len(rotatedLayout) {
+		return time.Time{}, 0, false
+	}
+	t, err := time.Parse(rotatedLayout, s[:len(rotatedLayout)])
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	n := 1
+	if seq := s[len(rotatedLayout):]; seq != "" {
+		if !strings.HasPrefix(seq, "_") {
+			return time.Time{}, 0, false
+		}
+		if n, err = strconv.Atoi(seq[1:]); err != nil || n < 2 {
+			return time.Time{}, 0, false
+		}
+	}
+	return t, n, true
+}
+
+// Close closes the file. Closing a closed logger does nothing.
+func (l *Logger) Close() error {
+	err := l.closeFile()
+	l.err = ErrClosed
+	return err
+}
@@ -0,0 +1,269 @@
+package datalog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+func Test_LoggerCSV(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.csv")
+
+	l, err := New(path, Options{Columns: []Column{ColTime, ColChannel, ColVolts, ColClipped}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	if err := l.Write(
+		Record{Time: ts, Channel: "a,b", Volts: 1.5},
+		Record{Time: ts, Channel: "c", Raw: 32767, Volts: 2.048, Clipped: true},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := "time,channel,volts,clipped\n" +
+		"2026-10-19T12:00:00Z,\"a,b\",1.5,false\n" +
+		"2026-10-19T12:00:00Z,c,2.048,true\n"
+	if got := readFile(t, path); got != exp {
+		t.Fatalf("exp = %q, got = %q", exp, got)
+	}
+
+	// Reopening appends without another header.
+	if l, err = New(path, Options{Columns: []Column{ColChannel}}); err != nil {
+		t.Fatal(err)
+	}
+	l.Write(Record{Channel: "d"})
+	l.Close()
+	if got := readFile(t, path); got != exp+"d\n" {
+		t.Fatalf("exp = %q, got = %q", exp+"d\n", got)
+	}
+}
+
+func Test_LoggerJSONL(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.jsonl")
+
+	l, err := New(path, Options{Format: JSONL, Sync: SyncAlways})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := FromReading(ads111x.Reading{
+		Channel: ads111x.Channel{Name: "ch0"},
+		Time:    time.Unix(0, 0),
+		Raw:     -32768,
+		Volts:   -2.048,
+	})
+	if !r.Clipped || r.Unit != "V" {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+	nan := Record{Channel: "nan", Value: math.NaN()}
+	if err := l.Write(r, nan); err != nil {
+		t.Fatal(err)
+	}
+	l.Close()
+
+	lines := strings.Split(strings.TrimSpace(readFile(t, path)), "\n")
+	exp := `{"time":"1970-01-01T00:00:00Z","channel":"ch0","raw":-32768,"volts":-2.048,"value":-2.048,"unit":"V","clipped":true}`
+	if lines[0] != exp {
+		t.Fatalf("exp = %s, got = %s", exp, lines[0])
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &m); err != nil {
+		t.Fatal(err)
+	} else if v, ok := m["value"]; !ok || v != nil {
+		t.Fatalf("exp = null value, got = %v", lines[1])
+	}
+}
+
+func Test_LoggerRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.csv")
+
+	clock := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	// Files that aren't rotated logs are left alone.
+	others := []string{"log-backup.csv", "log-20261019T000000Z_x.csv", "log-20261019T000000Z.csv.bak"}
+	for _, name := range others {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := New(path, Options{
+		Columns:  []Column{ColChannel},
+		MaxSize:  20,
+		MaxAge:   time.Hour,
+		Gzip:     true,
+		MaxFiles: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// The header and three records fill the file.
+	l.Write(Record{Channel: "aaaa"}, Record{Channel: "bbbb"}, Record{Channel: "cccc"})
+	// Rotated by size.
+	l.Write(Record{Channel: "dddd"})
+	// Rotated by size again in the same second.
+	l.Write(Record{Channel: "eeee"}, Record{Channel: "ffff"}, Record{Channel: "gggg"})
+	l.Write(Record{Channel: "hhhh"})
+	// Rotated by age.
+	clock = clock.Add(time.Hour)
+	l.Write(Record{Channel: "iiii"})
+
+	names, err := l.Rotated()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The oldest was pruned.
+	exp := []string{"log-20261019T000000Z_2.csv.gz", "log-20261019T010000Z.csv.gz"}
+	if len(names) != len(exp) {
+		t.Fatalf("exp = %v, got = %v", exp, names)
+	}
+	for i := range exp {
+		if filepath.Base(names[i]) != exp[i] {
+			t.Fatalf("exp = %v, got = %v", exp, names)
+		}
+	}
+
+	f, err := os.Open(names[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(bufio.NewReader(zr))
+	if got := string(b); got != "channel\nhhhh\n" {
+		t.Fatalf("exp = %q, got = %q", "channel\nhhhh\n", got)
+	}
+	if got := readFile(t, path); got != "channel\niiii\n" {
+		t.Fatalf("exp = %q, got = %q", "channel\niiii\n", got)
+	}
+	for _, name := range others {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_LoggerRotateError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.csv")
+
+	// The oldest rotated file can't be pruned.
+	old := filepath.Join(dir, "log-20261018T000000Z.csv")
+	if err := os.MkdirAll(filepath.Join(old, "x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := New(path, Options{Columns: []Column{ColChannel}, MaxFiles: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	if err := l.Write(Record{Channel: "aaaa"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Rotate(); err == nil {
+		t.Fatal("expected prune error")
+	}
+
+	// The new file was opened anyway.
+	if err := l.Write(Record{Channel: "bbbb"}); err != nil {
+		t.Fatal(err)
+	} else if err := l.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "channel\nbbbb\n" {
+		t.Fatalf("exp = %q, got = %q", "channel\nbbbb\n", got)
+	}
+}
+
+func Test_LoggerClose(t *testing.T) {
+	dir := t.TempDir()
+	l, err := New(filepath.Join(dir, "log.csv"), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	} else if err := l.Close(); err != nil {
+		t.Fatalf("exp = nil, got = %v", err)
+	}
+	if err := l.Write(Record{}); err != ErrClosed {
+		t.Fatalf("exp = %v, got = %v", ErrClosed, err)
+	}
+
+	// If a new file can't be opened, the logger is left closed.
+	if l, err = New(filepath.Join(dir, "log.csv"), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	rerr := l.Rotate()
+	if rerr == nil {
+		t.Fatal("expected open error")
+	}
+	if err := l.Write(Record{}); err != rerr {
+		t.Fatalf("exp = %v, got = %v", rerr, err)
+	} else if err := l.Rotate(); err != rerr {
+		t.Fatalf("exp = %v, got = %v", rerr, err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("exp = nil, got = %v", err)
+	} else if err := l.Write(Record{}); err != ErrClosed {
+		t.Fatalf("exp = %v, got = %v", ErrClosed, err)
+	}
+}
+
+func Test_Options(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.csv")
+	for _, opts := range []Options{
+		{Format: "xml"},
+		{Sync: "sometimes"},
+		{Sync: SyncInterval},
+	} {
+		if _, err := New(path, opts); err == nil {
+			t.Fatalf("expected error for %+v", opts)
+		}
+	}
+
+	cols, err := ParseColumns("time, Volts,clipped")
+	if err != nil {
+		t.Fatal(err)
+	} else if len(cols) != 3 || cols[1] != ColVolts {
+		t.Fatalf("unexpected columns: %v", cols)
+	}
+	if _, err := ParseColumns("time,volt"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
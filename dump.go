@@ -0,0 +1,179 @@
+package ads111x
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+)
+
+// Register default values, from the datasheet.
+const (
+	DefaultConversion = uint16(0x0000)
+	DefaultLoThresh   = uint16(0x8000)
+	DefaultHiThresh   = uint16(0x7fff)
+)
+
+// Field is a decoded config register field.
+type Field struct {
+	// Name is the datasheet name of the field, e.g., MUX.
+	Name string `json:"name"`
+	// Bits is the field's bit range in the register, e.g., 14:12.
+	Bits  string `json:"bits"`
+	Value uint16 `json:"value"`
+	// Symbol is the name of the value's constant, e.g., AIN_0_1.
+	Symbol   string `json:"symbol"`
+	Default  bool   `json:"default"`
+	Reserved bool   `json:"reserved,omitempty"`
+}
+
+// Register is a decoded conversion or threshold register.
+type Register struct {
+	Addr  byte   `json:"addr"`
+	Value uint16 `json:"value"`
+	// Signed is the two's complement value.
+	Signed int16 `json:"signed"`
+	// Volts is the value at the config's full scale range.
+	Volts   float64 `json:"volts"`
+	Default bool    `json:"default"`
+}
+
+// ConfigRegister is the decoded config register.
+type ConfigRegister struct {
+	Addr    byte    `json:"addr"`
+	Value   uint16  `json:"value"`
+	Default bool    `json:"default"`
+	Fields  []Field `json:"fields"`
+}
+
+// RegisterDump is a decoded snapshot of the device's registers.
+type RegisterDump struct {
+	Conversion Register       `json:"conversion"`
+	Config     ConfigRegister `json:"config"`
+	LoThresh   Register       `json:"lo_thresh"`
+	HiThresh   Register       `json:"hi_thresh"`
+	// Notes explain unusual settings.
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Dump reads and decodes the device's registers.
+func (adc *ADC) Dump() (*RegisterDump, error) {
+	var vals [4]uint16
+	for reg := range vals {
+		v, err := adc.ReadRegUint16(byte(reg))
+		if err != nil {
+			return nil, err
+		}
+		vals[reg] = v
+	}
+	return DecodeRegisters(vals[ConversionReg], vals[ConfigReg], vals[LoThreshReg], vals[HiThreshReg]), nil
+}
+
+// DecodeRegisters decodes register values, e.g., ones read by hand.
+func DecodeRegisters(conv, cfg, lo, hi uint16) *RegisterDump {
+	fs := Scale(cfg & Scale_Mask)
+	reg := func(addr byte, v, def uint16) Register {
+		return Register{Addr: addr, Value: v, Signed: int16(v), Volts: Volts(v, fs), Default: v == def}
+	}
+	d := &RegisterDump{
+		Conversion: reg(ConversionReg, conv, DefaultConversion),
+		Config: ConfigRegister{
+			Addr:    ConfigReg,
+			Value:   cfg,
+			Default: cfg == DefaultConfig,
+			Fields:  DecodeConfig(cfg),
+		},
+		LoThresh: reg(LoThreshReg, lo, DefaultLoThresh),
+		HiThresh: reg(HiThreshReg, hi, DefaultHiThresh),
+	}
+	for _, f := range d.Config.Fields {
+		if f.Reserved {
+			d.Notes = append(d.Notes, fmt.Sprintf("%s value %d is reserved", f.Name, f.Value))
+		}
+	}
+	switch {
+	case hi&0x8000 != 0 && lo&0x8000 == 0:
+		d.Notes = append(d.Notes, "ALERT/RDY is a conversion ready pin")
+	case int16(lo) >= int16(hi):
+		d.Notes = append(d.Notes, "Lo_thresh is not less than Hi_thresh")
+	}
+	return d
+}
+
+// configFields are the config register fields, most significant first, by
+// their datasheet names and the names of their text forms.
+var configFields = []struct {
+	name     string
+	text     string
+	readOnly bool
+	*enum
+}{
+	{"OS", "status", true, statusEnum},
+	{"MUX", "input", false, ainEnum},
+	{"PGA", "scale", false, scaleEnum},
+	{"MODE", "mode", false, modeEnum},
+	{"DR", "rate", false, dataRateEnum},
+	{"COMP_MODE", "comp-mode", false, comparatorModeEnum},
+	{"COMP_POL", "comp-polarity", false, comparatorPolarityEnum},
+	{"COMP_LAT", "comp-latch", false, comparatorLatchingEnum},
+	{"COMP_QUE", "comp-queue", false, comparatorQueueEnum},
+}
+
+// DecodeConfig decodes the fields of a config register value.
+func DecodeConfig(cfg uint16) []Field {
+	fields := make([]Field, 0, len(configFields))
+	for _, cf := range configFields {
+		f := Field{
+			Name:    cf.name,
+			Value:   (cfg & cf.mask) >> cf.lsb,
+			Default: cfg&cf.mask == DefaultConfig&cf.mask,
+		}
+		if width := bitsLen(cf.mask >> cf.lsb); width == 1 {
+			f.Bits = fmt.Sprint(cf.lsb)
+		} else {
+			f.Bits = fmt.Sprintf("%d:%d", int(cf.lsb)+width-1, cf.lsb)
+		}
+		if i, ok := cf.index(cfg & cf.mask); ok {
+			f.Symbol = cf.names[i]
+		} else {
+			f.Symbol = "reserved"
+			f.Reserved = true
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func bitsLen(v uint16) int {
+	n := 0
+	for ; v != 0; v >>= 1 {
+		n++
+	}
+	return n
+}
+
+// String returns the dump as text. Non-default values are marked with *.
+func (d *RegisterDump) String() string {
+	var buf bytes.Buffer
+	tw := tabwriter.NewWriter(&buf, 0, 8, 2, ' ', 0)
+	mark := func(def bool) string {
+		if def {
+			return ""
+		}
+		return "*"
+	}
+	reg := func(name string, r Register) {
+		fmt.Fprintf(tw, "%s\t0x%02x\t0x%04x%s\t%d\t%f V\n", name, r.Addr, r.Value, mark(r.Default), r.Signed, r.Volts)
+	}
+	reg("Conversion", d.Conversion)
+	fmt.Fprintf(tw, "Config\t0x%02x\t0x%04x%s\n", d.Config.Addr, d.Config.Value, mark(d.Config.Default))
+	for _, f := range d.Config.Fields {
+		fmt.Fprintf(tw, "  %s\t[%s]\t%d%s\t%s\n", f.Name, f.Bits, f.Value, mark(f.Default), f.Symbol)
+	}
+	reg("Lo_thresh", d.LoThresh)
+	reg("Hi_thresh", d.HiThresh)
+	tw.Flush()
+	for _, n := range d.Notes {
+		fmt.Fprintf(&buf, "note: %s\n", n)
+	}
+	return buf.String()
+}
@@ -0,0 +1,91 @@
+package ads111x
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Dump(t *testing.T) {
+	dev := fakedev.New()
+	dev.SetReg(ConversionReg, 0x4000)
+	dev.SetReg(ConfigReg, DefaultConfig)
+	dev.SetReg(LoThreshReg, DefaultLoThresh)
+	dev.SetReg(HiThreshReg, DefaultHiThresh)
+	adc := newFakeADC(dev)
+	d, err := adc.Dump()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Config.Default || !d.LoThresh.Default || d.Conversion.Default {
+		t.Fatalf("unexpected defaults: %+v", d)
+	}
+	if d.Conversion.Volts != 1.024 || d.LoThresh.Signed != -32768 {
+		t.Fatalf("unexpected registers: %+v", d)
+	}
+	var syms []string
+	for _, f := range d.Config.Fields {
+		syms = append(syms, f.Symbol)
+	}
+	exp := "Idle AIN_0_1 Scale_2_048V Single DR_128SPS Traditional ActiveLow Off Disable"
+	if got := strings.Join(syms, " "); got != exp {
+		t.Fatalf("exp = %s, got = %s", exp, got)
+	}
+	if f := d.Config.Fields[1]; f.Name != "MUX" || f.Bits != "14:12" {
+		t.Fatalf("unexpected field: %+v", f)
+	}
+	if f := d.Config.Fields[3]; f.Bits != "8" {
+		t.Fatalf("exp = 8, got = %s", f.Bits)
+	}
+	if len(d.Notes) != 0 {
+		t.Fatalf("unexpected notes: %q", d.Notes)
+	}
+}
+
+func Test_DecodeRegisters(t *testing.T) {
+	// Reserved scale, AIN_2_GND, window mode, conversion ready thresholds.
+	d := DecodeRegisters(0x0100, 0xee93, 0x0000, 0x8000)
+	fs := d.Config.Fields
+	if f := fs[2]; !f.Reserved || f.Symbol != "reserved" || f.Default {
+		t.Fatalf("unexpected field: %+v", f)
+	}
+	if f := fs[1]; f.Symbol != "AIN_2_GND" || f.Default {
+		t.Fatalf("unexpected field: %+v", f)
+	}
+	if f := fs[5]; f.Symbol != "Window" {
+		t.Fatalf("exp = Window, got = %s", f.Symbol)
+	}
+	// The reserved scales are +/- 0.256V.
+	if exp := 0.256 * 0x100 / 0x8000; d.Conversion.Volts != exp {
+		t.Fatalf("exp = %f, got = %f", exp, d.Conversion.Volts)
+	}
+	if len(d.Notes) != 2 {
+		t.Fatalf("exp = 2 notes, got = %q", d.Notes)
+	}
+
+	// Ignore column widths.
+	s := strings.Join(strings.Fields(d.String()), " ")
+	for _, exp := range []string{
+		"Config 0x01 0xee93* OS",
+		"PGA [11:9] 7* reserved",
+		"COMP_QUE [1:0] 3 Disable",
+		"note: ALERT/RDY is a conversion ready pin",
+	} {
+		if !strings.Contains(s, exp) {
+			t.Fatalf("exp = %q in\n%s", exp, s)
+		}
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got RegisterDump
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	} else if got.Config.Fields[2] != fs[2] || got.HiThresh != d.HiThresh {
+		t.Fatalf("exp = %+v, got = %+v", d, got)
+	}
+}
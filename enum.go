@@ -0,0 +1,345 @@
+package ads111x
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enum describes the values of a config field type for String, MarshalText
+// and UnmarshalText.
+type enum struct {
+	typ  string
+	lsb  uint8
+	mask uint16
+	// names are the constant names and text the text forms, indexed by
+	// field value.
+	names []string
+	text  []string
+	// alias maps a normalized name to the form it's matched in, e.g.,
+	// "2.048v" and "scale2048v" both to "2048".
+	alias func(s string) string
+}
+
+var (
+	statusEnum = &enum{typ: "Status", lsb: Status_LSB, mask: Status_Mask,
+		names: []string{"Busy", "Idle"},
+		text:  []string{"busy", "idle"}}
+	ainEnum = &enum{typ: "AIN", lsb: AIN_LSB, mask: AIN_Mask,
+		names: []string{"AIN_0_1", "AIN_0_3", "AIN_1_3", "AIN_2_3", "AIN_0_GND", "AIN_1_GND", "AIN_2_GND", "AIN_3_GND"},
+		text:  []string{"ain0-ain1", "ain0-ain3", "ain1-ain3", "ain2-ain3", "ain0-gnd", "ain1-gnd", "ain2-gnd", "ain3-gnd"},
+		alias: func(s string) string {
+			// ain0-ain1 and 0-1 match, as do ain0, ain0-gnd and 0-gnd.
+			s = strings.Replace(s, "ain", "", -1)
+			if len(s) == 1 {
+				s += "gnd"
+			}
+			return s
+		}}
+	scaleEnum = &enum{typ: "Scale", lsb: Scale_LSB, mask: Scale_Mask,
+		names: []string{"Scale_6_144V", "Scale_4_096V", "Scale_2_048V", "Scale_1_024V", "Scale_0_512V", "Scale_0_256V"},
+		text:  []string{"6.144V", "4.096V", "2.048V", "1.024V", "0.512V", "0.256V"},
+		alias: func(s string) string {
+			s = strings.TrimSuffix(strings.TrimPrefix(s, "scale"), "v")
+			return strings.Replace(s, ".", "", -1)
+		}}
+	modeEnum = &enum{typ: "Mode", lsb: Mode_LSB, mask: Mode_Mask,
+		names: []string{"Continuous", "Single"},
+		text:  []string{"continuous", "single"}}
+	dataRateEnum = &enum{typ: "DataRate", lsb: DataRate_LSB, mask: DataRate_Mask,
+		names: []string{"DR_8SPS", "DR_16SPS", "DR_32SPS", "DR_64SPS", "DR_128SPS", "DR_250SPS", "DR_475SPS", "DR_860SPS"},
+		text:  []string{"8sps", "16sps", "32sps", "64sps", "128sps", "250sps", "475sps", "860sps"},
+		alias: func(s string) string {
+			return strings.TrimSuffix(strings.TrimPrefix(s, "dr"), "sps")
+		}}
+	comparatorModeEnum = &enum{typ: "ComparatorMode", lsb: ComparatorMode_LSB, mask: ComparatorMode_Mask,
+		names: []string{"Traditional", "Window"},
+		text:  []string{"traditional", "window"}}
+	comparatorPolarityEnum = &enum{typ: "ComparatorPolarity", lsb: ComparatorPolarity_LSB, mask: ComparatorPolarity_Mask,
+		names: []string{"ActiveLow", "ActiveHigh"},
+		text:  []string{"active-low", "active-high"}}
+	comparatorLatchingEnum = &enum{typ: "ComparatorLatching", lsb: ComparatorLatching_LSB, mask: ComparatorLatching_Mask,
+		names: []string{"Off", "On"},
+		text:  []string{"off", "on"}}
+	comparatorQueueEnum = &enum{typ: "ComparatorQueue", lsb: ComparatorQueue_LSB, mask: ComparatorQueue_Mask,
+		names: []string{"AfterOne", "AfterTwo", "AfterFour", "Disable"},
+		text:  []string{"after-one", "after-two", "after-four", "disable"}}
+)
+
+// index returns the field value of v, and whether it has a name.
+func (e *enum) index(v uint16) (int, bool) {
+	i := int(v&e.mask) >> e.lsb
+	return i, v&^e.mask == 0 && i < len(e.names)
+}
+
+func (e *enum) String(v uint16) string {
+	if i, ok := e.index(v); ok {
+		return e.names[i]
+	}
+	return fmt.Sprintf("%s(0x%x)", e.typ, v)
+}
+
+func (e *enum) marshal(v uint16) ([]byte, error) {
+	if i, ok := e.index(v); ok {
+		return []byte(e.text[i]), nil
+	}
+	return nil, fmt.Errorf("invalid %s 0x%x", e.typ, v)
+}
+
+// parse parses a constant name or text form, ignoring case, spaces,
+// dashes, underscores and a leading +/-.
+func (e *enum) parse(s string) (uint16, error) {
+	norm := func(s string) string {
+		s = strings.ToLower(s)
+		s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "+/-"), "±")
+		s = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+		if e.alias != nil {
+			s = e.alias(s)
+		}
+		return s
+	}
+	n := norm(s)
+	for i := range e.names {
+		if n == norm(e.names[i]) || n == norm(e.text[i]) {
+			return uint16(i) << e.lsb, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid %s %q, must be one of: %s", e.typ, s, strings.Join(e.text, ", "))
+}
+
+// String returns the constant name of the status, e.g., Idle.
+func (s Status) String() string { return statusEnum.String(uint16(s)) }
+
+// MarshalText returns the text form of the status, e.g., idle.
+func (s Status) MarshalText() ([]byte, error) { return statusEnum.marshal(uint16(s)) }
+
+// UnmarshalText parses a status's constant name or text form.
+func (s *Status) UnmarshalText(b []byte) error {
+	v, err := statusEnum.parse(string(b))
+	if err == nil {
+		*s = Status(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (s *Status) Set(v string) error { return s.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the input, e.g., AIN_0_GND.
+func (a AIN) String() string { return ainEnum.String(uint16(a)) }
+
+// MarshalText returns the text form of the input, e.g., ain0-gnd.
+func (a AIN) MarshalText() ([]byte, error) { return ainEnum.marshal(uint16(a)) }
+
+// UnmarshalText parses an input's constant name or text form. Inputs can
+// also be written without "ain", e.g., 0-1, and single-ended inputs
+// without "gnd", e.g., ain0.
+func (a *AIN) UnmarshalText(b []byte) error {
+	v, err := ainEnum.parse(string(b))
+	if err == nil {
+		*a = AIN(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (a *AIN) Set(v string) error { return a.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the scale, e.g., Scale_2_048V.
+func (s Scale) String() string { return scaleEnum.String(uint16(s)) }
+
+// MarshalText returns the text form of the scale, e.g., 2.048V.
+func (s Scale) MarshalText() ([]byte, error) { return scaleEnum.marshal(uint16(s)) }
+
+// UnmarshalText parses a scale's constant name or text form. The V is
+// optional, e.g., 2.048.
+func (s *Scale) UnmarshalText(b []byte) error {
+	v, err := scaleEnum.parse(string(b))
+	if err == nil {
+		*s = Scale(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (s *Scale) Set(v string) error { return s.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the mode, e.g., Single.
+func (m Mode) String() string { return modeEnum.String(uint16(m)) }
+
+// MarshalText returns the text form of the mode, e.g., single.
+func (m Mode) MarshalText() ([]byte, error) { return modeEnum.marshal(uint16(m)) }
+
+// UnmarshalText parses a mode's constant name or text form.
+func (m *Mode) UnmarshalText(b []byte) error {
+	v, err := modeEnum.parse(string(b))
+	if err == nil {
+		*m = Mode(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (m *Mode) Set(v string) error { return m.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the data rate, e.g., DR_128SPS.
+func (dr DataRate) String() string { return dataRateEnum.String(uint16(dr)) }
+
+// MarshalText returns the text form of the data rate, e.g., 128sps.
+func (dr DataRate) MarshalText() ([]byte, error) { return dataRateEnum.marshal(uint16(dr)) }
+
+// UnmarshalText parses a data rate's constant name or text form. The sps is
+// optional, e.g., 128.
+func (dr *DataRate) UnmarshalText(b []byte) error {
+	v, err := dataRateEnum.parse(string(b))
+	if err == nil {
+		*dr = DataRate(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (dr *DataRate) Set(v string) error { return dr.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the comparator mode, e.g., Window.
+func (cm ComparatorMode) String() string { return comparatorModeEnum.String(uint16(cm)) }
+
+// MarshalText returns the text form of the comparator mode, e.g., window.
+func (cm ComparatorMode) MarshalText() ([]byte, error) {
+	return comparatorModeEnum.marshal(uint16(cm))
+}
+
+// UnmarshalText parses a comparator mode's constant name or text form.
+func (cm *ComparatorMode) UnmarshalText(b []byte) error {
+	v, err := comparatorModeEnum.parse(string(b))
+	if err == nil {
+		*cm = ComparatorMode(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (cm *ComparatorMode) Set(v string) error { return cm.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the comparator polarity, e.g.,
+// ActiveLow.
+func (cp ComparatorPolarity) String() string { return comparatorPolarityEnum.String(uint16(cp)) }
+
+// MarshalText returns the text form of the comparator polarity, e.g.,
+// active-low.
+func (cp ComparatorPolarity) MarshalText() ([]byte, error) {
+	return comparatorPolarityEnum.marshal(uint16(cp))
+}
+
+// UnmarshalText parses a comparator polarity's constant name or text form.
+func (cp *ComparatorPolarity) UnmarshalText(b []byte) error {
+	v, err := comparatorPolarityEnum.parse(string(b))
+	if err == nil {
+		*cp = ComparatorPolarity(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (cp *ComparatorPolarity) Set(v string) error { return cp.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the comparator latching, e.g., Off.
+func (cl ComparatorLatching) String() string { return comparatorLatchingEnum.String(uint16(cl)) }
+
+// MarshalText returns the text form of the comparator latching, e.g., off.
+func (cl ComparatorLatching) MarshalText() ([]byte, error) {
+	return comparatorLatchingEnum.marshal(uint16(cl))
+}
+
+// UnmarshalText parses a comparator latching's constant name or text form.
+func (cl *ComparatorLatching) UnmarshalText(b []byte) error {
+	v, err := comparatorLatchingEnum.parse(string(b))
+	if err == nil {
+		*cl = ComparatorLatching(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (cl *ComparatorLatching) Set(v string) error { return cl.UnmarshalText([]byte(v)) }
+
+// String returns the constant name of the comparator queue, e.g., Disable.
+func (cq ComparatorQueue) String() string { return comparatorQueueEnum.String(uint16(cq)) }
+
+// MarshalText returns the text form of the comparator queue, e.g., disable.
+func (cq ComparatorQueue) MarshalText() ([]byte, error) {
+	return comparatorQueueEnum.marshal(uint16(cq))
+}
+
+// UnmarshalText parses a comparator queue's constant name or text form.
+func (cq *ComparatorQueue) UnmarshalText(b []byte) error {
+	v, err := comparatorQueueEnum.parse(string(b))
+	if err == nil {
+		*cq = ComparatorQueue(v)
+	}
+	return err
+}
+
+// Set implements flag.Value.
+func (cq *ComparatorQueue) Set(v string) error { return cq.UnmarshalText([]byte(v)) }
+
+// FieldNames returns the names of the config fields' text forms, most
+// significant first, e.g., status, input, scale.
+func FieldNames() []string {
+	names := make([]string, len(configFields))
+	for i, f := range configFields {
+		names[i] = f.text
+	}
+	return names
+}
+
+// configField returns the index of the named config field, ignoring case.
+func configField(name string) (int, error) {
+	for i, f := range configFields {
+		if strings.EqualFold(f.text, name) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown field %q, must be one of: %s", name, strings.Join(FieldNames(), ", "))
+}
+
+// FieldText returns the text form of the named field in cfg, e.g., 2.048V
+// for scale. Reserved values use their String form.
+func FieldText(cfg uint16, name string) (string, error) {
+	i, err := configField(name)
+	if err != nil {
+		return "", err
+	}
+	f := configFields[i]
+	if b, err := f.marshal(cfg & f.mask); err == nil {
+		return string(b), nil
+	}
+	return f.String(cfg & f.mask), nil
+}
+
+// ConfigText returns the text forms of cfg's fields by name, e.g.,
+// "scale": "2.048V".
+func ConfigText(cfg uint16) map[string]string {
+	m := make(map[string]string, len(configFields))
+	for _, f := range configFields {
+		m[f.text], _ = FieldText(cfg, f.text)
+	}
+	return m
+}
+
+// SetFieldText parses the text form or constant name of a value for the
+// named field and sets the field in cfg. Status is read-only.
+func SetFieldText(cfg uint16, name, value string) (uint16, error) {
+	i, err := configField(name)
+	if err != nil {
+		return cfg, err
+	}
+	f := configFields[i]
+	if f.readOnly {
+		return cfg, fmt.Errorf("%s is read-only", f.text)
+	}
+	v, err := f.parse(value)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg&^f.mask | v, nil
+}
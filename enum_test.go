@@ -0,0 +1,166 @@
+package ads111x
+
+import (
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func Test_EnumString(t *testing.T) {
+	test := func(exp string, v fmtStringer) {
+		if got := v.String(); got != exp {
+			t.Fatalf("exp = %s, got = %s", exp, got)
+		}
+	}
+	test("Idle", Idle)
+	test("AIN_2_GND", AIN_2_GND)
+	test("Scale_0_512V", Scale_0_512V)
+	test("Scale(0xe00)", Scale(7<<Scale_LSB))
+	test("Single", Single)
+	test("DR_860SPS", DR_860SPS)
+	test("Window", Window)
+	test("ActiveHigh", ActiveHigh)
+	test("On", On)
+	test("AfterFour", AfterFour)
+}
+
+type fmtStringer interface {
+	String() string
+}
+
+func Test_EnumText(t *testing.T) {
+	// Every value round trips through its text form.
+	for _, e := range []*enum{statusEnum, ainEnum, scaleEnum, modeEnum, dataRateEnum,
+		comparatorModeEnum, comparatorPolarityEnum, comparatorLatchingEnum, comparatorQueueEnum} {
+		for i := range e.names {
+			v := uint16(i) << e.lsb
+			b, err := e.marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range []string{string(b), e.names[i]} {
+				if got, err := e.parse(s); err != nil {
+					t.Fatal(err)
+				} else if got != v {
+					t.Fatalf("%s: exp = 0x%x, got = 0x%x", s, v, got)
+				}
+			}
+		}
+	}
+
+	if _, err := Scale(7 << Scale_LSB).MarshalText(); err == nil {
+		t.Fatal("expected error for reserved scale")
+	}
+
+	var in AIN
+	for _, s := range []string{"ain1-gnd", "AIN_1_GND", "1-gnd", "ain1", "1", "Ain1 - GND"} {
+		in = AIN_0_1
+		if err := in.UnmarshalText([]byte(s)); err != nil {
+			t.Fatal(err)
+		} else if in != AIN_1_GND {
+			t.Fatalf("%s: exp = %v, got = %v", s, AIN_1_GND, in)
+		}
+	}
+	if err := in.Set("0-3"); err != nil || in != AIN_0_3 {
+		t.Fatalf("exp = %v, got = %v, %v", AIN_0_3, in, err)
+	}
+	if err := in.Set("ain4"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	var fs Scale
+	for _, s := range []string{"2.048V", "2.048", "+/-2.048V", "±2.048v", "Scale_2_048V"} {
+		fs = Scale_6_144V
+		if err := fs.Set(s); err != nil {
+			t.Fatal(err)
+		} else if fs != Scale_2_048V {
+			t.Fatalf("%s: exp = %v, got = %v", s, Scale_2_048V, fs)
+		}
+	}
+
+	var dr DataRate
+	for _, s := range []string{"128sps", "128", "DR_128SPS", "128 SPS"} {
+		dr = DR_8SPS
+		if err := dr.Set(s); err != nil {
+			t.Fatal(err)
+		} else if dr != DR_128SPS {
+			t.Fatalf("%s: exp = %v, got = %v", s, DR_128SPS, dr)
+		}
+	}
+	if err := dr.Set("100sps"); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_EnumJSON(t *testing.T) {
+	type config struct {
+		Queue ComparatorQueue `json:"queue"`
+		Scale Scale           `json:"scale"`
+		Rate  DataRate        `json:"rate"`
+		Pol   ComparatorPolarity
+	}
+	b, err := json.Marshal(config{AfterTwo, Scale_4_096V, DR_475SPS, ActiveHigh})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `{"queue":"after-two","scale":"4.096V","rate":"475sps","Pol":"active-high"}`
+	if string(b) != exp {
+		t.Fatalf("exp = %s, got = %s", exp, b)
+	}
+	var c config
+	if err := json.Unmarshal([]byte(`{"queue":"disable","scale":"0.256","rate":"DR_8SPS","Pol":"ActiveLow"}`), &c); err != nil {
+		t.Fatal(err)
+	} else if c != (config{Disable, Scale_0_256V, DR_8SPS, ActiveLow}) {
+		t.Fatalf("unexpected config: %+v", c)
+	}
+}
+
+func Test_EnumFlag(t *testing.T) {
+	var (
+		mode Mode
+		cm   ComparatorMode
+		cl   ComparatorLatching
+		st   Status
+	)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&mode, "mode", "")
+	fs.Var(&cm, "comp-mode", "")
+	fs.Var(&cl, "latch", "")
+	fs.Var(&st, "status", "")
+	if err := fs.Parse([]string{"-mode", "single", "-comp-mode", "WINDOW", "-latch", "on", "-status", "idle"}); err != nil {
+		t.Fatal(err)
+	}
+	if mode != Single || cm != Window || cl != On || st != Idle {
+		t.Fatalf("unexpected values: %v %v %v %v", mode, cm, cl, st)
+	}
+}
+
+func Test_FieldText(t *testing.T) {
+	cfg, err := SetFieldText(DefaultConfig, "scale", "4.096")
+	if err != nil {
+		t.Fatal(err)
+	} else if exp := DefaultConfig&^Scale_Mask | uint16(Scale_4_096V); cfg != exp {
+		t.Fatalf("exp = 0x%x, got = 0x%x", exp, cfg)
+	}
+	if cfg, err = SetFieldText(cfg, "Comp-Queue", "after-two"); err != nil {
+		t.Fatal(err)
+	} else if s, _ := FieldText(cfg, "comp-queue"); s != "after-two" {
+		t.Fatalf("exp = after-two, got = %s", s)
+	}
+
+	m := ConfigText(cfg | 7<<Scale_LSB)
+	if len(m) != len(FieldNames()) || m["scale"] != "Scale(0xe00)" || m["input"] != "ain0-ain1" {
+		t.Fatalf("unexpected fields: %v", m)
+	}
+
+	if _, err := SetFieldText(cfg, "status", "busy"); err == nil || err.Error() != "status is read-only" {
+		t.Fatalf("exp = status is read-only, got = %v", err)
+	}
+	if _, err := SetFieldText(cfg, "gain", "2"); err == nil || !strings.HasPrefix(err.Error(), `unknown field "gain"`) {
+		t.Fatalf("exp = unknown field, got = %v", err)
+	}
+	if _, err := SetFieldText(cfg, "rate", "9sps"); err == nil {
+		t.Fatal("expected error")
+	}
+}
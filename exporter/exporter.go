@@ -0,0 +1,225 @@
+// Package exporter serves the channels in a config file as Prometheus
+// metrics, along with I2C transaction, error, latency and clipping metrics.
+// Channels are sampled on their own schedule and scrapes return the latest
+// samples, so a scrape never waits on the bus.
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/config"
+	"github.com/dgnorton/ads111x/sensors"
+)
+
+// DefaultInterval is the time between samples of each channel.
+const DefaultInterval = 10 * time.Second
+
+// now is for test purposes.
+var now = time.Now
+
+// Content types for the exposition formats.
+const (
+	textType        = "text/plain; version=0.0.4; charset=utf-8"
+	openMetricsType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// latencyBuckets are the conversion latency histogram buckets, in seconds.
+// Each sample waits for a conversion, which takes 1.2 ms at 860 SPS and
+// 125 ms at 8 SPS, up to 10% longer with the device's clock error, plus the
+// time on the bus around it.
+var latencyBuckets = []float64{.001, .002, .005, .01, .02, .05, .1, .2, .5, 1}
+
+// Options configure an Exporter.
+type Options struct {
+	// Interval is the time between samples of each channel. It defaults
+	// to DefaultInterval.
+	Interval time.Duration
+}
+
+// Exporter samples channels and serves them as metrics. It implements
+// http.Handler.
+type Exporter struct {
+	interval time.Duration
+	buses    map[string][]*channel
+
+	mu           sync.Mutex
+	value        *family
+	volts        *family
+	timestamp    *family
+	clipped      *family
+	sampleErrors *family
+	latency      *family
+	txs          *family
+	txErrors     *family
+}
+
+// channel is a configured channel and its label values.
+type channel struct {
+	*config.Channel
+	labels []string
+}
+
+// New returns an exporter for the devices. It traces transactions on the
+// devices' buses, so the buses shouldn't be traced by anything else.
+func New(d *config.Devices, opts Options) *Exporter {
+	chLabels := []string{"bus", "address", "input", "name"}
+	e := &Exporter{
+		interval:  opts.Interval,
+		buses:     make(map[string][]*channel),
+		value:     newFamily("ads111x_channel_value", gauge, "Channel value in engineering units.", append(chLabels, "unit")...),
+		volts:     newFamily("ads111x_channel_volts", gauge, "Calibrated voltage at the channel input.", chLabels...),
+		timestamp: newFamily("ads111x_channel_last_sample_timestamp_seconds", gauge, "Time of the channel's last successful sample.", chLabels...),
+		clipped:   newFamily("ads111x_channel_clipped", counter, "Samples at either end of the channel's scale.", chLabels...),
+		sampleErrors: newFamily("ads111x_channel_errors", counter,
+			"Failed channel samples by type: i2c, or range when the value is outside the sensor's range.", append(chLabels, "type")...),
+		latency: newHistogram("ads111x_conversion_duration_seconds",
+			"Time to configure the ADC for a channel, wait for a conversion and read it.", latencyBuckets, chLabels...),
+		txs: newFamily("ads111x_i2c_transactions", counter, "I2C transactions by device address.", "bus", "address"),
+		txErrors: newFamily("ads111x_i2c_errors", counter,
+			"Failed I2C transactions by type: nack, timeout, arbitration, io or other.", "bus", "address", "type"),
+	}
+	if e.interval <= 0 {
+		e.interval = DefaultInterval
+	}
+
+	for _, c := range d.Channels {
+		input, _ := c.Input.MarshalText()
+		ch := &channel{
+			Channel: c,
+			labels:  []string{c.ADC.Bus, fmt.Sprintf("0x%x", uint8(c.ADC.Spec.Address)), string(input), c.Name},
+		}
+		e.buses[c.ADC.Bus] = append(e.buses[c.ADC.Bus], ch)
+		// Start the counters at zero so rates work from the first error.
+		e.clipped.add(0, ch.labels...)
+	}
+	for dev := range e.buses {
+		if b, ok := d.Bus(dev); ok {
+			b.SetTrace(e.tracer(dev))
+		}
+	}
+	return e
+}
+
+// tracer returns a bus trace func that counts transactions and errors.
+func (e *Exporter) tracer(dev string) func(ads111x.Tx) {
+	return func(tx ads111x.Tx) {
+		addr := fmt.Sprintf("0x%x", tx.Addr)
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		e.txs.add(1, dev, addr)
+		if tx.Err != nil {
+			e.txErrors.add(1, dev, addr, errorType(tx.Err))
+		}
+	}
+}
+
+// errorType classifies an I2C error by its errno.
+func errorType(err error) string {
+	if ads111x.IsNACK(err) {
+		return "nack"
+	}
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return "other"
+	}
+	switch errno {
+	case syscall.ETIMEDOUT:
+		return "timeout"
+	case syscall.EAGAIN:
+		return "arbitration"
+	case syscall.EIO:
+		return "io"
+	}
+	return "other"
+}
+
+// Run samples every channel each interval until ctx is done. Each bus is
+// sampled by its own goroutine.
+func (e *Exporter) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, chs := range e.buses {
+		wg.Add(1)
+		go func(chs []*channel) {
+			defer wg.Done()
+			t := time.NewTicker(e.interval)
+			defer t.Stop()
+			for {
+				e.sampleAll(chs)
+				select {
+				case <-t.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(chs)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// Sample samples every channel once.
+func (e *Exporter) Sample() {
+	for _, chs := range e.buses {
+		e.sampleAll(chs)
+	}
+}
+
+func (e *Exporter) sampleAll(chs []*channel) {
+	for _, c := range chs {
+		e.sample(c)
+	}
+}
+
+// sample samples a channel and records it. The lock isn't held while the
+// bus is in use.
+func (e *Exporter) sample(c *channel) {
+	start := now()
+	s, err := c.Sample()
+	finish := now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err != nil {
+		typ := "i2c"
+		if errors.Is(err, sensors.ErrOutOfRange) {
+			typ = "range"
+		}
+		e.sampleErrors.add(1, append(c.labels, typ)...)
+		return
+	}
+	e.latency.observe(finish.Sub(start).Seconds(), c.labels...)
+	e.value.set(s.Value, append(c.labels, s.Unit)...)
+	e.volts.set(s.Volts, c.labels...)
+	e.timestamp.set(float64(finish.UnixNano())/1e9, c.labels...)
+	if s.Clipped {
+		e.clipped.add(1, c.labels...)
+	}
+}
+
+// ServeHTTP writes the metrics in the OpenMetrics format if the request
+// accepts it, or the Prometheus text format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	var buf bytes.Buffer
+	e.mu.Lock()
+	writeFamilies(&buf, []*family{
+		e.value, e.volts, e.timestamp, e.clipped, e.sampleErrors, e.latency, e.txs, e.txErrors,
+	}, openMetrics)
+	e.mu.Unlock()
+
+	if openMetrics {
+		w.Header().Set("Content-Type", openMetricsType)
+	} else {
+		w.Header().Set("Content-Type", textType)
+	}
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,186 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/config"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+const testYAML = `
+buses:
+  - device: /dev/i2c-1
+    adcs:
+      - address: 0x48
+        channels:
+          - name: battery
+            input: ain0-gnd
+            sensor: {type: divider, top: 30000, bottom: 10000}
+          - name: tank
+            input: ain1-gnd
+            sensor: {type: resistance, supply: 1.5, series: 10000}
+`
+
+func Test_Exporter(t *testing.T) {
+	dev := newFakeDevice(0x7fff, 0x7fff)
+	e := newTestExporter(t, dev)
+
+	clock := time.Unix(100, 0)
+	defer func() { now = time.Now }()
+	now = func() time.Time {
+		clock = clock.Add(3 * time.Millisecond)
+		return clock
+	}
+
+	e.Sample()
+	dev.SetFail(syscall.ENXIO)
+	e.Sample()
+
+	got := scrape(t, e, "")
+	labels := `bus="/dev/i2c-1",address="0x48",input="ain0-gnd",name="battery"`
+	for _, exp := range []string{
+		"# TYPE ads111x_channel_value gauge\n",
+		`ads111x_channel_value{` + labels + `,unit="V"} 8.19175` + "\n",
+		`ads111x_channel_volts{` + labels + `} 2.0479375` + "\n",
+		`ads111x_channel_last_sample_timestamp_seconds{` + labels + `} 100.006` + "\n",
+		"# TYPE ads111x_channel_clipped_total counter\n",
+		`ads111x_channel_clipped_total{` + labels + `} 1` + "\n",
+		`ads111x_channel_errors_total{` + labels + `,type="i2c"} 1` + "\n",
+		// 2.048V is outside the resistance sensor's range.
+		`ads111x_channel_errors_total{bus="/dev/i2c-1",address="0x48",input="ain1-gnd",name="tank",type="range"} 1` + "\n",
+		`ads111x_conversion_duration_seconds_bucket{` + labels + `,le="0.002"} 0` + "\n",
+		`ads111x_conversion_duration_seconds_bucket{` + labels + `,le="0.005"} 1` + "\n",
+		`ads111x_conversion_duration_seconds_bucket{` + labels + `,le="+Inf"} 1` + "\n",
+		`ads111x_conversion_duration_seconds_sum{` + labels + `} 0.003` + "\n",
+		`ads111x_conversion_duration_seconds_count{` + labels + `} 1` + "\n",
+		`ads111x_i2c_transactions_total{bus="/dev/i2c-1",address="0x48"} 10` + "\n",
+		`ads111x_i2c_errors_total{bus="/dev/i2c-1",address="0x48",type="nack"} 2` + "\n",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Fatalf("exp = %q in:\n%s", exp, got)
+		}
+	}
+	if strings.Contains(got, "# EOF") {
+		t.Fatal("unexpected EOF in text format")
+	}
+
+	got = scrape(t, e, "application/openmetrics-text; version=1.0.0")
+	for _, exp := range []string{
+		"# TYPE ads111x_channel_clipped counter\n",
+		`ads111x_channel_clipped_total{` + labels + `} 1` + "\n",
+	} {
+		if !strings.Contains(got, exp) {
+			t.Fatalf("exp = %q in:\n%s", exp, got)
+		}
+	}
+	if !strings.HasSuffix(got, "# EOF\n") {
+		t.Fatal("exp = # EOF at the end")
+	}
+}
+
+func Test_Latency(t *testing.T) {
+	dev := newFakeDevice(0x1000, 0x1000)
+	e := newTestExporter(t, dev)
+	e.Sample()
+
+	// Each sample waits for its conversion, at the default 128 SPS.
+	ct := ads111x.ConversionTime(ads111x.DR_128SPS)
+	min := (ct - time.Duration(float64(ct)*ads111x.DataRateTolerance)).Seconds()
+	got := scrape(t, e, "")
+	for _, name := range []string{"battery", "tank"} {
+		re := regexp.MustCompile(`ads111x_conversion_duration_seconds_sum\{[^}]*name="` + name + `"\} (\S+)`)
+		m := re.FindStringSubmatch(got)
+		if m == nil {
+			t.Fatalf("no duration for %s in:\n%s", name, got)
+		}
+		if sum, err := strconv.ParseFloat(m[1], 64); err != nil {
+			t.Fatal(err)
+		} else if sum < min {
+			t.Fatalf("exp >= %v, got = %v", min, sum)
+		}
+	}
+}
+
+func Test_Run(t *testing.T) {
+	dev := newFakeDevice(0x1000, 0x1000)
+	e := newTestExporter(t, dev)
+	e.interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- e.Run(ctx) }()
+
+	// Scrapes don't wait on the bus, and see samples as they're made.
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(scrape(t, e, ""), `name="tank",unit="Ω"}`) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a sample")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("exp = %v, got = %v", context.Canceled, err)
+	}
+}
+
+func Test_errorType(t *testing.T) {
+	for err, exp := range map[error]string{
+		syscall.ENXIO:                           "nack",
+		fmt.Errorf("tx: %w", syscall.ETIMEDOUT): "timeout",
+		syscall.EAGAIN:                          "arbitration",
+		syscall.EIO:                             "io",
+		syscall.EINVAL:                          "other",
+		fmt.Errorf("oops"):                      "other",
+	} {
+		if got := errorType(err); got != exp {
+			t.Fatalf("exp = %s, got = %s", exp, got)
+		}
+	}
+}
+
+// newFakeDevice returns a device whose AIN0 and AIN1 convert to ain0 and
+// ain1. Its conversions finish before the first status poll, so the
+// transaction counts are exact.
+func newFakeDevice(ain0, ain1 uint16) *fakedev.Device {
+	dev := fakedev.New()
+	dev.SetInput(uint16(ads111x.AIN_0_GND), ain0)
+	dev.SetInput(uint16(ads111x.AIN_1_GND), ain1)
+	dev.SetSpeed(2)
+	return dev
+}
+
+func newTestExporter(t *testing.T, dev *fakedev.Device) *Exporter {
+	f, err := config.Parse([]byte(testYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := f.OpenWith(func(string) (*ads111x.Bus, error) {
+		return ads111x.NewBus(dev), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(d, Options{})
+}
+
+func scrape(t *testing.T, e *Exporter, accept string) string {
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, r)
+	b, _ := io.ReadAll(w.Body)
+	return string(b)
+}
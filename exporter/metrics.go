@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric types.
+const (
+	gauge     = "gauge"
+	counter   = "counter"
+	histogram = "histogram"
+)
+
+// family is a metric and its series. Counter names don't include the
+// _total suffix, which is added when they're written.
+type family struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series is one set of label values and its value, or its bucket counts
+// for a histogram.
+type series struct {
+	values []string
+	value  float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newFamily(name, typ, help string, labels ...string) *family {
+	return &family{name: name, help: help, typ: typ, labels: labels, series: make(map[string]*series)}
+}
+
+func newHistogram(name, help string, buckets []float64, labels ...string) *family {
+	f := newFamily(name, histogram, help, labels...)
+	f.buckets = buckets
+	return f
+}
+
+func (f *family) with(values ...string) *series {
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{values: values}
+		if f.typ == histogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) set(v float64, values ...string) { f.with(values...).value = v }
+
+func (f *family) add(v float64, values ...string) { f.with(values...).value += v }
+
+func (f *family) observe(v float64, values ...string) {
+	s := f.with(values...)
+	for i, b := range f.buckets {
+		if v <= b {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// write writes the family in the Prometheus text format, or OpenMetrics.
+// Series are sorted by their label values.
+func (f *family) write(w *bufio.Writer, openMetrics bool) {
+	name := f.name
+	if f.typ == counter && !openMetrics {
+		name += "_total"
+	}
+	w.WriteString("# HELP " + name + " " + helpEscaper.Replace(f.help) + "\n")
+	w.WriteString("# TYPE " + name + " " + f.typ + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := f.series[k]
+		switch f.typ {
+		case counter:
+			f.sample(w, f.name+"_total", s.values, "", "", s.value)
+		case histogram:
+			for i, b := range f.buckets {
+				f.sample(w, f.name+"_bucket", s.values, "le", formatFloat(b), float64(s.counts[i]))
+			}
+			f.sample(w, f.name+"_bucket", s.values, "le", "+Inf", float64(s.count))
+			f.sample(w, f.name+"_sum", s.values, "", "", s.sum)
+			f.sample(w, f.name+"_count", s.values, "", "", float64(s.count))
+		default:
+			f.sample(w, f.name, s.values, "", "", s.value)
+		}
+	}
+}
+
+// sample writes one sample line, with an extra label if name is set.
+func (f *family) sample(w *bufio.Writer, metric string, values []string, name, value string, v float64) {
+	w.WriteString(metric)
+	if len(values) > 0 || name != "" {
+		sep := "{"
+		for i, lv := range values {
+			w.WriteString(sep + f.labels[i] + `="` + labelEscaper.Replace(lv) + `"`)
+			sep = ","
+		}
+		if name != "" {
+			w.WriteString(sep + name + `="` + value + `"`)
+		}
+		w.WriteString("}")
+	}
+	w.WriteString(" " + formatFloat(v) + "\n")
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeFamilies writes the families, followed by the EOF marker for
+// OpenMetrics.
+func writeFamilies(w io.Writer, fams []*family, openMetrics bool) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range fams {
+		f.write(bw, openMetrics)
+	}
+	if openMetrics {
+		bw.WriteString("# EOF\n")
+	}
+	return bw.Flush()
+}
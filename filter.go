@@ -0,0 +1,266 @@
+package ads111x
+
+import (
+	"fmt"
+	"math"
+)
+
+// Filter is a streaming digital filter. Filters that depend on the sample
+// rate compute their coefficients in SetRate.
+type Filter interface {
+	// SetRate sets the sample rate in samples per second, recomputes
+	// coefficients and resets the filter's state.
+	SetRate(sps float64) error
+	// Filter filters the next sample.
+	Filter(x float64) float64
+	// Reset clears the filter's state.
+	Reset()
+}
+
+// FilterChain is a filter that applies filters in order.
+type FilterChain []Filter
+
+// SetRate sets the sample rate of each filter.
+func (c FilterChain) SetRate(sps float64) error {
+	for _, f := range c {
+		if err := f.SetRate(sps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filter passes x through each filter in order.
+func (c FilterChain) Filter(x float64) float64 {
+	for _, f := range c {
+		x = f.Filter(x)
+	}
+	return x
+}
+
+// Reset resets each filter.
+func (c FilterChain) Reset() {
+	for _, f := range c {
+		f.Reset()
+	}
+}
+
+// MovingAverage is the average of the last n samples.
+type MovingAverage struct {
+	buf  []float64
+	next int
+	full bool
+	sum  float64
+}
+
+// NewMovingAverage returns a moving average over n samples.
+func NewMovingAverage(n int) *MovingAverage {
+	if n < 1 {
+		n = 1
+	}
+	return &MovingAverage{buf: make([]float64, n)}
+}
+
+// SetRate resets the filter. A moving average doesn't depend on the rate.
+func (m *MovingAverage) SetRate(sps float64) error {
+	m.Reset()
+	return nil
+}
+
+// Filter adds x and returns the average.
+func (m *MovingAverage) Filter(x float64) float64 {
+	m.sum += x - m.buf[m.next]
+	m.buf[m.next] = x
+	m.next++
+	if m.next == len(m.buf) {
+		m.next, m.full = 0, true
+	}
+	if m.full {
+		return m.sum / float64(len(m.buf))
+	}
+	return m.sum / float64(m.next)
+}
+
+// Reset clears the samples.
+func (m *MovingAverage) Reset() {
+	for i := range m.buf {
+		m.buf[i] = 0
+	}
+	m.next, m.full, m.sum = 0, false, 0
+}
+
+// LowPass is a single pole IIR low-pass filter.
+type LowPass struct {
+	cutoff float64
+	alpha  float64
+	y      float64
+	primed bool
+}
+
+// NewLowPass returns a single pole low-pass filter with a -3 dB cutoff
+// frequency in Hz.
+func NewLowPass(cutoff float64) *LowPass {
+	return &LowPass{cutoff: cutoff, alpha: 1}
+}
+
+// SetRate computes the filter's coefficient for the sample rate.
+func (f *LowPass) SetRate(sps float64) error {
+	if err := checkFreq(f.cutoff, sps); err != nil {
+		return err
+	}
+	f.alpha = 1 - math.Exp(-2*math.Pi*f.cutoff/sps)
+	f.Reset()
+	return nil
+}
+
+// Filter filters the next sample.
+func (f *LowPass) Filter(x float64) float64 {
+	if !f.primed {
+		// Start from the first sample rather than ramping up from 0.
+		f.y, f.primed = x, true
+		return x
+	}
+	f.y += f.alpha * (x - f.y)
+	return f.y
+}
+
+// Reset clears the filter's state.
+func (f *LowPass) Reset() {
+	f.y, f.primed = 0, false
+}
+
+// BiquadType is the response of a Biquad filter.
+type BiquadType int
+
+const (
+	// BiquadLowPass passes frequencies below the center frequency.
+	BiquadLowPass BiquadType = iota
+	// BiquadHighPass passes frequencies above the center frequency.
+	BiquadHighPass
+	// BiquadBandPass passes frequencies near the center frequency.
+	BiquadBandPass
+	// BiquadNotch rejects frequencies near the center frequency.
+	BiquadNotch
+)
+
+// Biquad is a second order IIR filter.
+type Biquad struct {
+	typ  BiquadType
+	freq float64
+	q    float64
+
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+// NewBiquad returns a second order filter with center or cutoff frequency
+// freq in Hz and quality factor q. A q of 0.7071 gives a Butterworth
+// response for low and high pass filters.
+func NewBiquad(typ BiquadType, freq, q float64) *Biquad {
+	return &Biquad{typ: typ, freq: freq, q: q, b0: 1}
+}
+
+// NewNotch returns a notch filter for mains interference at freq, usually 50
+// or 60 Hz.
+func NewNotch(freq float64) *Biquad {
+	return NewBiquad(BiquadNotch, freq, 10)
+}
+
+// SetRate computes the filter's coefficients for the sample rate using the
+// formulas from Robert Bristow-Johnson's Audio EQ Cookbook.
+func (f *Biquad) SetRate(sps float64) error {
+	if err := checkFreq(f.freq, sps); err != nil {
+		return err
+	}
+	if f.q <= 0 {
+		return fmt.Errorf("invalid q %g", f.q)
+	}
+	w0 := 2 * math.Pi * f.freq / sps
+	cos, alpha := math.Cos(w0), math.Sin(w0)/(2*f.q)
+
+	var b0, b1, b2 float64
+	switch f.typ {
+	case BiquadLowPass:
+		b0, b1, b2 = (1-cos)/2, 1-cos, (1-cos)/2
+	case BiquadHighPass:
+		b0, b1, b2 = (1+cos)/2, -(1 + cos), (1+cos)/2
+	case BiquadBandPass:
+		b0, b1, b2 = alpha, 0, -alpha
+	case BiquadNotch:
+		b0, b1, b2 = 1, -2*cos, 1
+	default:
+		return fmt.Errorf("invalid biquad type %d", f.typ)
+	}
+	a0 := 1 + alpha
+	f.b0, f.b1, f.b2 = b0/a0, b1/a0, b2/a0
+	f.a1, f.a2 = -2*cos/a0, (1-alpha)/a0
+	f.Reset()
+	return nil
+}
+
+// Filter filters the next sample.
+func (f *Biquad) Filter(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// Reset clears the filter's state.
+func (f *Biquad) Reset() {
+	f.x1, f.x2, f.y1, f.y2 = 0, 0, 0, 0
+}
+
+// checkFreq returns an error if freq can't be represented at the sample rate.
+func checkFreq(freq, sps float64) error {
+	if freq <= 0 || freq >= sps/2 {
+		return fmt.Errorf("%g Hz must be between 0 and %g Hz (half of %g SPS)", freq, sps/2, sps)
+	}
+	return nil
+}
+
+// FilteredInput reads an input through a filter. Each Read is treated as
+// the next sample, so it should be called once per conversion.
+type FilteredInput struct {
+	adc    *ADC
+	input  AIN
+	filter Filter
+	rate   DataRate
+	primed bool
+}
+
+// Filtered returns the input with filters applied in order. The filters'
+// coefficients are recomputed whenever the ADC's data rate changes.
+func (adc *ADC) Filtered(input AIN, filters ...Filter) *FilteredInput {
+	return &FilteredInput{
+		adc:    adc,
+		input:  input,
+		filter: FilterChain(filters),
+	}
+}
+
+// Read reads the input in volts and returns the filtered value.
+func (fi *FilteredInput) Read() (float64, error) {
+	cfg, err := fi.adc.Config()
+	if err != nil {
+		return 0, err
+	}
+	// The config is read on every conversion anyway, so a rate change made
+	// with SetDataRate, here or elsewhere, is picked up on the next read.
+	if dr := DataRate(cfg & DataRate_Mask); !fi.primed || dr != fi.rate {
+		if err := fi.filter.SetRate(SamplesPerSecond(dr)); err != nil {
+			return 0, err
+		}
+		fi.rate, fi.primed = dr, true
+	}
+	cnt, _, err := fi.adc.readAIN(cfg, fi.input)
+	if err != nil {
+		return 0, err
+	}
+	return fi.filter.Filter(Volts(cnt, Scale(cfg&Scale_Mask))), nil
+}
+
+// Reset clears the filters' state.
+func (fi *FilteredInput) Reset() {
+	fi.filter.Reset()
+}
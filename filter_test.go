@@ -0,0 +1,92 @@
+package ads111x
+
+import (
+	"math"
+	"testing"
+)
+
+func Test_MovingAverage(t *testing.T) {
+	m := NewMovingAverage(3)
+	for i, exp := range []float64{1, 1.5, 2, 3, 4} {
+		if got := m.Filter(float64(i + 1)); got != exp {
+			t.Fatalf("exp = %f, got = %f", exp, got)
+		}
+	}
+}
+
+func Test_Filters(t *testing.T) {
+	// rms returns the RMS of the filter's output for a sine at freq, after
+	// letting the filter settle.
+	rms := func(f Filter, freq float64) float64 {
+		if err := f.SetRate(860); err != nil {
+			t.Fatal(err)
+		}
+		var sumSq float64
+		for i, x := range sine(4300, 860, freq, 1, 0) {
+			y := f.Filter(x)
+			if i >= 860 {
+				sumSq += y * y
+			}
+		}
+		return math.Sqrt(sumSq / 3440)
+	}
+	const in = 1 / math.Sqrt2
+
+	check := func(name string, f Filter, freq, gain, tol float64) {
+		f.Reset()
+		if got := rms(f, freq) / in; math.Abs(got-gain) > tol {
+			t.Fatalf("%s at %v Hz: exp gain = %f, got = %f", name, freq, gain, got)
+		}
+	}
+	check("low pass", NewLowPass(10), 1, 1, 0.01)
+	check("low pass", NewLowPass(10), 10, 1/math.Sqrt2, 0.05)
+	check("biquad low pass", NewBiquad(BiquadLowPass, 20, 0.7071), 200, 0, 0.02)
+	check("biquad high pass", NewBiquad(BiquadHighPass, 20, 0.7071), 1, 0, 0.01)
+	check("biquad high pass", NewBiquad(BiquadHighPass, 20, 0.7071), 200, 1, 0.02)
+	check("biquad band pass", NewBiquad(BiquadBandPass, 100, 2), 100, 1, 0.01)
+	check("notch", NewNotch(50), 50, 0, 0.01)
+	check("notch", NewNotch(60), 120, 1, 0.05)
+
+	if err := NewNotch(60).SetRate(64); err == nil {
+		t.Fatal("expected error for notch above Nyquist")
+	}
+}
+
+func Test_FilteredInput(t *testing.T) {
+	adc := newInputTestADC(map[AIN]uint16{AIN_0_GND: 0x4000})
+	defer mustClose(adc)
+
+	spy := &rateFilter{}
+	fi := adc.Filtered(AIN_0_GND, spy, NewMovingAverage(2))
+	if v, err := fi.Read(); err != nil {
+		t.Fatal(err)
+	} else if v != 1.024 {
+		t.Fatalf("exp = 1.024, got = %f", v)
+	}
+	if err := adc.SetDataRate(DR_860SPS); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fi.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fi.Read(); err != nil {
+		t.Fatal(err)
+	}
+	if len(spy.rates) != 2 || spy.rates[0] != 128 || spy.rates[1] != 860 {
+		t.Fatalf("exp = [128 860], got = %v", spy.rates)
+	}
+}
+
+// rateFilter records the rates it's set to.
+type rateFilter struct {
+	rates []float64
+}
+
+func (f *rateFilter) SetRate(sps float64) error {
+	f.rates = append(f.rates, sps)
+	return nil
+}
+
+func (f *rateFilter) Filter(x float64) float64 { return x }
+
+func (f *rateFilter) Reset() {}
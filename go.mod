@@ -0,0 +1,17 @@
+module github.com/dgnorton/ads111x
+
+go 1.25.0
+
+require (
+	golang.org/x/exp v0.0.0-20230725093048-515e97ebf090
+	google.golang.org/grpc v1.82.1
+	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/net v0.53.0 // indirect
+	golang.org/x/sys v0.43.0 // indirect
+	golang.org/x/text v0.36.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260414002931-afd174a4e478 // indirect
+)
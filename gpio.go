@@ -0,0 +1,145 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNoAlertLine is returned when waiting for an alert on an ADC without an
+// alert line.
+var ErrNoAlertLine = errors.New("no alert line")
+
+// Edge is a GPIO line transition.
+type Edge int
+
+const (
+	// RisingEdge is a low to high transition.
+	RisingEdge Edge = iota
+	// FallingEdge is a high to low transition.
+	FallingEdge
+)
+
+// AlertLine is a GPIO input connected to the ALERT/RDY pin.
+type AlertLine interface {
+	// WaitEdge blocks until the line sees the edge it was requested with or
+	// ctx is done, and returns when the edge occurred.
+	WaitEdge(ctx context.Context) (time.Time, error)
+	// Active returns true if the line is at the level its edge goes to.
+	Active() (bool, error)
+	// Close releases the line.
+	Close() error
+}
+
+// lineOpener opens a GPIO line for edge detection.
+type lineOpener func(chip string, offset int, edge Edge) (AlertLine, error)
+
+// openLine is for test purposes.
+var openLine lineOpener = OpenGPIOLine
+
+// AlertEdge returns the edge the ALERT/RDY pin makes when it asserts with
+// the given polarity.
+func AlertEdge(cp ComparatorPolarity) Edge {
+	if cp == ActiveHigh {
+		return RisingEdge
+	}
+	return FallingEdge
+}
+
+// OpenAlert opens the GPIO line connected to the ALERT/RDY pin, e.g., chip
+// /dev/gpiochip0 line 17, requesting the edge that matches the configured
+// comparator polarity. The line is closed by Close. If the polarity is
+// changed later, OpenAlert must be called again. If the pin is already
+// asserted, e.g., latched before the line was opened, the alert is pending.
+func (adc *ADC) OpenAlert(chip string, offset int) error {
+	cp, err := adc.ComparatorPolarity()
+	if err != nil {
+		return err
+	}
+	line, err := openLine(chip, offset, AlertEdge(cp))
+	if err != nil {
+		return err
+	}
+	adc.SetAlertLine(line)
+	if active, err := line.Active(); err != nil {
+		return err
+	} else if active {
+		return adc.alertSeen(now())
+	}
+	return nil
+}
+
+// SetAlertLine sets the line connected to the ALERT/RDY pin, closing the
+// previous one.
+func (adc *ADC) SetAlertLine(line AlertLine) {
+	if adc.alert != nil {
+		adc.alert.Close()
+	}
+	adc.alert = line
+}
+
+// WaitAlert blocks until the ALERT/RDY pin asserts or ctx is done. The
+// alert is then pending until ClearAlert is called.
+func (adc *ADC) WaitAlert(ctx context.Context) (time.Time, error) {
+	if adc.alert == nil {
+		return time.Time{}, ErrNoAlertLine
+	}
+	t, err := adc.alert.WaitEdge(ctx)
+	if err != nil {
+		return t, err
+	}
+	return t, adc.alertSeen(t)
+}
+
+// EnableConversionReady configures the ALERT/RDY pin to pulse when each
+// conversion is ready, instead of acting as a comparator output. This is
+// done by setting the MSB of Hi_thresh to 1 and the MSB of Lo_thresh to 0.
+func (adc *ADC) EnableConversionReady() error {
+	if err := adc.SetThresholds(0, -1<<15); err != nil {
+		return err
+	}
+	cq, err := adc.ComparatorQueue()
+	if err != nil {
+		return err
+	}
+	if cq == Disable {
+		return adc.SetComparatorQueue(AfterOne)
+	}
+	return nil
+}
+
+// conversionReady returns true if the ALERT/RDY pin is configured to pulse
+// when each conversion is ready; see EnableConversionReady.
+func (adc *ADC) conversionReady() (bool, error) {
+	lo, hi, err := adc.Thresholds()
+	if err != nil {
+		return false, err
+	}
+	cq, err := adc.ComparatorQueue()
+	if err != nil {
+		return false, err
+	}
+	return hi < 0 && lo >= 0 && cq != Disable, nil
+}
+
+// ReadAINReady starts a single-shot conversion on input and waits for the
+// ALERT/RDY pin to signal it's ready instead of polling. The ADC must be in
+// Single mode with conversion ready enabled; see EnableConversionReady.
+func (adc *ADC) ReadAINReady(ctx context.Context, input AIN) (uint16, error) {
+	if adc.alert == nil {
+		return 0, ErrNoAlertLine
+	}
+	cfg, err := adc.Config()
+	if err != nil {
+		return 0, err
+	}
+	// Writing 1 to the status bit starts a conversion.
+	cfg = cfg&^AIN_Mask | uint16(input) | Status_Mask
+	if err := adc.WriteConfig(cfg); err != nil {
+		return 0, err
+	}
+	if _, err := adc.alert.WaitEdge(ctx); err != nil {
+		return 0, err
+	}
+	return adc.ReadRegUint16(ConversionReg)
+}
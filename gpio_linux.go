@@ -0,0 +1,153 @@
+package ads111x
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// GPIO character device uAPI v2, from linux/gpio.h.
+const (
+	gpioV2LinesMax         = 64
+	gpioMaxNameSize        = 32
+	gpioV2LineNumAttrsMax  = 10
+	gpioV2GetLineIoctl     = 0xc250b407
+	gpioV2GetValuesIoctl   = 0xc010b40e
+	gpioV2LineFlagInput    = 1 << 2
+	gpioV2LineFlagEdgeRise = 1 << 4
+	gpioV2LineFlagEdgeFall = 1 << 5
+	gpioV2LineFlagRealtime = 1 << 11
+	gpioV2LineEventSize    = 48
+)
+
+type gpioV2LineAttribute struct {
+	id      uint32
+	padding uint32
+	value   uint64
+}
+
+type gpioV2LineConfigAttribute struct {
+	attr gpioV2LineAttribute
+	mask uint64
+}
+
+type gpioV2LineConfig struct {
+	flags    uint64
+	numAttrs uint32
+	padding  [5]uint32
+	attrs    [gpioV2LineNumAttrsMax]gpioV2LineConfigAttribute
+}
+
+type gpioV2LineRequest struct {
+	offsets         [gpioV2LinesMax]uint32
+	consumer        [gpioMaxNameSize]byte
+	config          gpioV2LineConfig
+	numLines        uint32
+	eventBufferSize uint32
+	padding         [5]uint32
+	fd              int32
+}
+
+type gpioV2LineValues struct {
+	bits uint64
+	mask uint64
+}
+
+// The ioctl number encodes the request's size, so the layout must match.
+var _ [592]byte = [unsafe.Sizeof(gpioV2LineRequest{})]byte{}
+var _ [16]byte = [unsafe.Sizeof(gpioV2LineValues{})]byte{}
+
+// gpioLine is a GPIO line requested for edge detection from the Linux GPIO
+// character device.
+type gpioLine struct {
+	f *os.File
+	// high is set if the line's edge is rising.
+	high bool
+}
+
+// OpenGPIOLine requests a line on a GPIO chip, e.g., /dev/gpiochip0, as an
+// input with edge detection.
+func OpenGPIOLine(chip string, offset int, edge Edge) (AlertLine, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid line offset %d", offset)
+	}
+	c, err := os.Open(chip)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	var req gpioV2LineRequest
+	req.offsets[0] = uint32(offset)
+	req.numLines = 1
+	copy(req.consumer[:], "ads111x")
+	req.config.flags = gpioV2LineFlagInput | gpioV2LineFlagRealtime
+	if edge == RisingEdge {
+		req.config.flags |= gpioV2LineFlagEdgeRise
+	} else {
+		req.config.flags |= gpioV2LineFlagEdgeFall
+	}
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, c.Fd(), gpioV2GetLineIoctl, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		return nil, fmt.Errorf("%s line %d: %w", chip, offset, errno)
+	}
+
+	// A non-blocking fd lets the runtime poller handle reads, so waits can
+	// be interrupted with a read deadline.
+	if err := syscall.SetNonblock(int(req.fd), true); err != nil {
+		syscall.Close(int(req.fd))
+		return nil, err
+	}
+	return &gpioLine{
+		f:    os.NewFile(uintptr(req.fd), fmt.Sprintf("%s:%d", chip, offset)),
+		high: edge == RisingEdge,
+	}, nil
+}
+
+// WaitEdge blocks until an edge event is read from the line or ctx is done.
+func (l *gpioLine) WaitEdge(ctx context.Context) (time.Time, error) {
+	stop := context.AfterFunc(ctx, func() {
+		l.f.SetReadDeadline(time.Now())
+	})
+	defer stop()
+	defer l.f.SetReadDeadline(time.Time{})
+
+	buf := make([]byte, gpioV2LineEventSize)
+	if _, err := io.ReadFull(l.f, buf); err != nil {
+		if ctx.Err() != nil {
+			return time.Time{}, ctx.Err()
+		}
+		return time.Time{}, err
+	}
+	ts := binary.NativeEndian.Uint64(buf[0:8])
+	return time.Unix(0, int64(ts)), nil
+}
+
+// Active reads the line's level.
+func (l *gpioLine) Active() (bool, error) {
+	vals := gpioV2LineValues{mask: 1}
+	rc, err := l.f.SyscallConn()
+	if err != nil {
+		return false, err
+	}
+	var errno syscall.Errno
+	if err := rc.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, gpioV2GetValuesIoctl, uintptr(unsafe.Pointer(&vals)))
+	}); err != nil {
+		return false, err
+	} else if errno != 0 {
+		return false, fmt.Errorf("%s: %w", l.f.Name(), errno)
+	}
+	high := vals.bits&1 != 0
+	return high == l.high, nil
+}
+
+// Close releases the line.
+func (l *gpioLine) Close() error {
+	return l.f.Close()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package ads111x
+
+import "errors"
+
+// OpenGPIOLine is only supported on Linux.
+func OpenGPIOLine(chip string, offset int, edge Edge) (AlertLine, error) {
+	return nil, errors.New("GPIO character device is only supported on Linux")
+}
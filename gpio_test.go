@@ -0,0 +1,124 @@
+package ads111x
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_OpenAlert(t *testing.T) {
+	defer func() { openLine = OpenGPIOLine }()
+	var gotEdge Edge
+	line := &fakeLine{}
+	openLine = func(chip string, offset int, edge Edge) (AlertLine, error) {
+		if chip != "/dev/gpiochip0" || offset != 17 {
+			t.Fatalf("unexpected line %s:%d", chip, offset)
+		}
+		gotEdge = edge
+		return line, nil
+	}
+
+	adc := newInputTestADC(nil)
+	if err := adc.OpenAlert("/dev/gpiochip0", 17); err != nil {
+		t.Fatal(err)
+	} else if gotEdge != FallingEdge {
+		t.Fatalf("exp = %v, got = %v", FallingEdge, gotEdge)
+	}
+
+	if err := adc.SetComparatorPolarity(ActiveHigh); err != nil {
+		t.Fatal(err)
+	}
+	if err := adc.OpenAlert("/dev/gpiochip0", 17); err != nil {
+		t.Fatal(err)
+	} else if gotEdge != RisingEdge {
+		t.Fatalf("exp = %v, got = %v", RisingEdge, gotEdge)
+	}
+
+	line.edges = make(chan time.Time, 1)
+	exp := time.Unix(1, 0)
+	line.edges <- exp
+	if got, err := adc.WaitAlert(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(exp) {
+		t.Fatalf("exp = %v, got = %v", exp, got)
+	}
+
+	mustClose(adc)
+	if !line.closed {
+		t.Fatal("exp line to be closed")
+	}
+}
+
+func Test_ReadAINReady(t *testing.T) {
+	dev := fakedev.New()
+	dev.SetInput(uint16(AIN_1_GND), 0x1234)
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+
+	if _, err := adc.ReadAINReady(context.Background(), AIN_1_GND); err != ErrNoAlertLine {
+		t.Fatalf("exp = %v, got = %v", ErrNoAlertLine, err)
+	}
+
+	if err := adc.EnableConversionReady(); err != nil {
+		t.Fatal(err)
+	}
+	if lo, hi := dev.Reg(LoThreshReg), dev.Reg(HiThreshReg); lo != 0 || hi != 0x8000 {
+		t.Fatalf("exp = 0x0000 and 0x8000, got = 0x%04x and 0x%04x", lo, hi)
+	}
+	if q := ComparatorQueue(dev.Reg(ConfigReg) & ComparatorQueue_Mask); q != AfterOne {
+		t.Fatalf("exp = %v, got = %v", AfterOne, q)
+	}
+
+	// The pin pulses when the conversion finishes.
+	line := &fakeLine{edges: make(chan time.Time, 1), delay: ConversionTime(DR_128SPS)}
+	line.edges <- time.Now()
+	adc.SetAlertLine(line)
+	if got, err := adc.ReadAINReady(context.Background(), AIN_1_GND); err != nil {
+		t.Fatal(err)
+	} else if got != 0x1234 {
+		t.Fatalf("exp = 0x1234, got = 0x%x", got)
+	}
+	if dev.Reg(ConfigReg)&Status_Mask == 0 {
+		t.Fatal("exp conversion to be started")
+	}
+
+	// Waiting is canceled with the context.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := adc.ReadAINReady(ctx, AIN_1_GND); err != context.Canceled {
+		t.Fatalf("exp = %v, got = %v", context.Canceled, err)
+	}
+}
+
+// fakeLine is an alert line whose edges are sent on edges. Waiting for one
+// takes delay, if set. If edges is nil, there's an edge after every delay.
+type fakeLine struct {
+	edges  chan time.Time
+	delay  time.Duration
+	active bool
+	closed bool
+}
+
+func (l *fakeLine) WaitEdge(ctx context.Context) (time.Time, error) {
+	sleep(l.delay)
+	if l.edges == nil {
+		return now(), nil
+	}
+	select {
+	case t := <-l.edges:
+		return t, nil
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
+
+func (l *fakeLine) Active() (bool, error) {
+	return l.active, nil
+}
+
+func (l *fakeLine) Close() error {
+	l.closed = true
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package influx encodes readings as InfluxDB line protocol and writes them
+// in batches to an InfluxDB 2.x /api/v2/write endpoint.
+package influx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// DefaultMeasurement is the measurement FromReading uses when none is given.
+const DefaultMeasurement = "adc"
+
+// Point is one line of line protocol. Field values are floats, signed or
+// unsigned integers, bools or strings. Points with a zero Time are written
+// without a timestamp, so the server uses its own clock.
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{}
+	Time        time.Time
+}
+
+// FromReading returns a point for a reading from an ads111x.Manager. It's
+// tagged with the bus, address, input, channel name and scale, and has raw,
+// volts and value fields. The value is the volts; replace it when the
+// channel has a transfer function.
+func FromReading(measurement, bus string, r ads111x.Reading) Point {
+	if measurement == "" {
+		measurement = DefaultMeasurement
+	}
+	input, _ := r.Input.MarshalText()
+	scale, _ := r.Scale.MarshalText()
+	return Point{
+		Measurement: measurement,
+		Tags: map[string]string{
+			"bus":     bus,
+			"address": fmt.Sprintf("0x%x", uint8(r.Addr)),
+			"input":   string(input),
+			"channel": r.Name,
+			"scale":   string(scale),
+		},
+		Fields: map[string]interface{}{
+			"raw":   r.Raw,
+			"volts": r.Volts,
+			"value": r.Volts,
+		},
+		Time: r.Time,
+	}
+}
+
+// String returns the point's line protocol without the trailing newline,
+// or an error message if it can't be encoded.
+func (p Point) String() string {
+	b, err := AppendPoint(nil, p)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return string(b[:len(b)-1])
+}
+
+// AppendPoint appends a point's line protocol, with a trailing newline, to
+// dst. Tags and fields are sorted by key, tags with empty values are left
+// out, as are NaN and infinite floats which InfluxDB can't store. It's an
+// error if no fields are left.
+func AppendPoint(dst []byte, p Point) ([]byte, error) {
+	if p.Measurement == "" {
+		return dst, errors.New("influx: empty measurement")
+	}
+	start := len(dst)
+	var err error
+	if dst, err = appendEscaped(dst, p.Measurement, measurementEscaper); err != nil {
+		return dst[:start], err
+	}
+
+	for _, k := range sortedKeys(p.Tags) {
+		v := p.Tags[k]
+		if v == "" {
+			continue
+		}
+		dst = append(dst, ',')
+		if dst, err = appendEscaped(dst, k, keyEscaper); err != nil {
+			return dst[:start], err
+		}
+		dst = append(dst, '=')
+		if dst, err = appendEscaped(dst, v, keyEscaper); err != nil {
+			return dst[:start], err
+		}
+	}
+
+	sep := byte(' ')
+	keys := make([]string, 0, len(p.Fields))
+	for k := range p.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, ok, err := appendValue(nil, p.Fields[k])
+		if err != nil {
+			return dst[:start], fmt.Errorf("influx: field %q: %v", k, err)
+		} else if !ok {
+			continue
+		}
+		dst = append(dst, sep)
+		sep = ','
+		if dst, err = appendEscaped(dst, k, keyEscaper); err != nil {
+			return dst[:start], err
+		}
+		dst = append(dst, '=')
+		dst = append(dst, v...)
+	}
+	if sep == ' ' {
+		return dst[:start], fmt.Errorf("influx: %s has no fields", p.Measurement)
+	}
+
+	if !p.Time.IsZero() {
+		dst = append(dst, ' ')
+		dst = strconv.AppendInt(dst, p.Time.UnixNano(), 10)
+	}
+	return append(dst, '\n'), nil
+}
+
+// appendValue appends a field value. It returns false for floats that
+// can't be stored.
+func appendValue(dst []byte, v interface{}) ([]byte, bool, error) {
+	switch v := v.(type) {
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return dst, false, nil
+		}
+		return strconv.AppendFloat(dst, v, 'g', -1, 64), true, nil
+	case float32:
+		return appendValue(dst, float64(v))
+	case int:
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), true, nil
+	case int8:
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), true, nil
+	case int16:
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), true, nil
+	case int32:
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), true, nil
+	case int64:
+		return append(strconv.AppendInt(dst, v, 10), 'i'), true, nil
+	case uint:
+		return append(strconv.AppendUint(dst, uint64(v), 10), 'u'), true, nil
+	case uint8:
+		return append(strconv.AppendUint(dst, uint64(v), 10), 'u'), true, nil
+	case uint16:
+		return append(strconv.AppendUint(dst, uint64(v), 10), 'u'), true, nil
+	case uint32:
+		return append(strconv.AppendUint(dst, uint64(v), 10), 'u'), true, nil
+	case uint64:
+		return append(strconv.AppendUint(dst, v, 10), 'u'), true, nil
+	case bool:
+		return strconv.AppendBool(dst, v), true, nil
+	case string:
+		dst = append(dst, '"')
+		dst = append(dst, stringEscaper.Replace(v)...)
+		return append(dst, '"'), true, nil
+	}
+	return dst, false, fmt.Errorf("unsupported type %T", v)
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// appendEscaped appends an escaped measurement, key or tag value. Line
+// protocol has no escape for newlines, so they're an error.
+func appendEscaped(dst []byte, s string, r *strings.Replacer) ([]byte, error) {
+	if strings.ContainsAny(s, "\r\n") {
+		return dst, fmt.Errorf("influx: newline in %q", s)
+	}
+	return append(dst, r.Replace(s)...), nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Encoder writes points as line protocol.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+}
+
+// NewEncoder returns an encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes the points. Nothing is written if any of them can't be
+// encoded.
+func (e *Encoder) Encode(pts ...Point) error {
+	buf := e.buf[:0]
+	for _, p := range pts {
+		var err error
+		if buf, err = AppendPoint(buf, p); err != nil {
+			return err
+		}
+	}
+	e.buf = buf
+	_, err := e.w.Write(buf)
+	return err
+}
@@ -0,0 +1,74 @@
+package influx
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+func Test_FromReading(t *testing.T) {
+	r := ads111x.Reading{
+		Channel: ads111x.Channel{Name: "tank level", Addr: 0x49, Input: ads111x.AIN_0_1},
+		Time:    time.Unix(1, 5),
+		Raw:     16384,
+		Volts:   1.024,
+		Scale:   ads111x.Scale_2_048V,
+	}
+	exp := `adc,address=0x49,bus=/dev/i2c-1,channel=tank\ level,input=ain0-ain1,scale=2.048V raw=16384i,value=1.024,volts=1.024 1000000005`
+	if got := FromReading("", "/dev/i2c-1", r).String(); got != exp {
+		t.Fatalf("exp = %s, got = %s", exp, got)
+	}
+}
+
+func Test_AppendPoint(t *testing.T) {
+	test := func(exp string, p Point) {
+		t.Helper()
+		b, err := AppendPoint(nil, p)
+		if err != nil {
+			t.Fatal(err)
+		} else if got := string(b); got != exp+"\n" {
+			t.Fatalf("exp = %q, got = %q", exp+"\n", got)
+		}
+	}
+	test(`a\,b\ c,empty\ =x,k\=1=v\,2\\ f="say \"hi\" \\",g=true,u=7u`, Point{
+		Measurement: "a,b c",
+		Tags:        map[string]string{"k=1": `v,2\`, "empty ": "x", "none": ""},
+		Fields:      map[string]interface{}{"f": `say "hi" \`, "g": true, "u": uint8(7)},
+	})
+	test("m x=1e+21,y=-0.5", Point{
+		Measurement: "m",
+		Fields:      map[string]interface{}{"x": 1e21, "y": float32(-0.5), "nan": math.NaN()},
+	})
+
+	for _, p := range []Point{
+		{Fields: map[string]interface{}{"x": 1}},
+		{Measurement: "m"},
+		{Measurement: "m", Fields: map[string]interface{}{"x": math.Inf(1)}},
+		{Measurement: "m", Fields: map[string]interface{}{"x": []int{1}}},
+		{Measurement: "m", Tags: map[string]string{"t": "a\nb"}, Fields: map[string]interface{}{"x": 1}},
+	} {
+		if b, err := AppendPoint([]byte("keep"), p); err == nil {
+			t.Fatalf("expected error for %+v", p)
+		} else if string(b) != "keep" {
+			t.Fatalf("exp = keep, got = %q", b)
+		}
+	}
+}
+
+func Test_Encoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	p := Point{Measurement: "m", Fields: map[string]interface{}{"x": 1}, Time: time.Unix(0, 1)}
+	if err := e.Encode(p, p); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Encode(p, Point{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if exp := "m x=1i 1\nm x=1i 1\n"; buf.String() != exp {
+		t.Fatalf("exp = %q, got = %q", exp, buf.String())
+	}
+}
@@ -0,0 +1,280 @@
+package influx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBatchSize is the number of points a Writer buffers before it
+// posts them.
+const DefaultBatchSize = 5000
+
+// Posts that fail with a temporary error are retried after a backoff that
+// starts at retryMin and doubles up to retryMax.
+const (
+	retryMin = time.Second
+	retryMax = time.Minute
+)
+
+// now is for test purposes.
+var now = time.Now
+
+// Options configure a Writer.
+type Options struct {
+	// URL is the write endpoint, e.g., http://localhost:8086/api/v2/write.
+	// A URL without a path has /api/v2/write added.
+	URL    string
+	Org    string
+	Bucket string
+	// Token is sent in the Authorization header, if set.
+	Token string
+	// BatchSize is the number of points buffered before they're posted. It
+	// defaults to DefaultBatchSize.
+	BatchSize int
+	// MaxBuffered is the most points kept while posts are failing. Beyond
+	// it, the oldest points are dropped. It defaults to 10 batches, and is
+	// at least BatchSize.
+	MaxBuffered int
+	// FlushInterval, if set, posts buffered points at least this often.
+	FlushInterval time.Duration
+	// Client defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// Error is a write the server rejected.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("influx: write failed: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("influx: write failed: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Temporary returns whether the write may succeed if it's retried.
+func (e *Error) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Writer batches points and posts them as line protocol. Points in a batch
+// that fails with a temporary error, or can't be sent at all, are kept, up
+// to MaxBuffered, and sent with a later one once the writer has backed off;
+// batches the server rejects are dropped. It's safe for concurrent use.
+type Writer struct {
+	url         string
+	token       string
+	batchSize   int
+	maxBuffered int
+	client      *http.Client
+
+	mu  sync.Mutex
+	buf []byte
+	// ends are where each buffered point ends in buf.
+	ends []int
+	// err is the error from the last background flush.
+	err error
+	// retryAt is when a failed post may be retried, and backoff is how
+	// long was waited for it.
+	retryAt time.Time
+	backoff time.Duration
+	dropped int64
+
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewWriter returns a writer.
+func NewWriter(opts Options) (*Writer, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("influx: invalid URL %q", opts.URL)
+	}
+	if opts.Bucket == "" {
+		return nil, errors.New("influx: no bucket")
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/api/v2/write"
+	}
+	q := u.Query()
+	if opts.Org != "" {
+		q.Set("org", opts.Org)
+	}
+	q.Set("bucket", opts.Bucket)
+	q.Set("precision", "ns")
+	u.RawQuery = q.Encode()
+
+	w := &Writer{
+		url:         u.String(),
+		token:       opts.Token,
+		batchSize:   opts.BatchSize,
+		maxBuffered: opts.MaxBuffered,
+		client:      opts.Client,
+		done:        make(chan struct{}),
+	}
+	if w.batchSize <= 0 {
+		w.batchSize = DefaultBatchSize
+	}
+	if w.maxBuffered <= 0 {
+		w.maxBuffered = 10 * w.batchSize
+	} else if w.maxBuffered < w.batchSize {
+		w.maxBuffered = w.batchSize
+	}
+	if w.client == nil {
+		w.client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.FlushInterval > 0 {
+		w.wg.Add(1)
+		go w.flushEvery(opts.FlushInterval)
+	}
+	return w, nil
+}
+
+// Write buffers points, and posts the buffer once it holds a batch unless
+// the writer is backing off after a failed post. It returns the error from
+// that post, or from the last background flush.
+func (w *Writer) Write(pts ...Point) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	buf, ends := w.buf, w.ends
+	for _, p := range pts {
+		var err error
+		if buf, err = AppendPoint(buf, p); err != nil {
+			return err
+		}
+		ends = append(ends, len(buf))
+	}
+	w.buf, w.ends = buf, ends
+	w.trim()
+	if len(w.ends) >= w.batchSize && !now().Before(w.retryAt) {
+		return w.flush()
+	}
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Dropped returns the number of points dropped, because the buffer was full
+// or the server rejected them.
+func (w *Writer) Dropped() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.dropped
+}
+
+// Flush posts the buffered points, even if the writer is backing off.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// Close stops background flushing and posts the buffered points.
+func (w *Writer) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	w.wg.Wait()
+	return w.Flush()
+}
+
+func (w *Writer) flushEvery(d time.Duration) {
+	defer w.wg.Done()
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			w.mu.Lock()
+			if !now().Before(w.retryAt) {
+				if err := w.flush(); err != nil {
+					w.err = err
+				}
+			}
+			w.mu.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// flush posts the buffer. If the post fails with a temporary error, the
+// points are kept and the backoff is doubled. w.mu must be held.
+func (w *Writer) flush() error {
+	w.err = nil
+	if len(w.ends) == 0 {
+		return nil
+	}
+	err := w.post(w.buf)
+	if e, ok := err.(*Error); err == nil || ok && !e.Temporary() {
+		if err != nil {
+			w.dropped += int64(len(w.ends))
+		}
+		w.buf, w.ends = w.buf[:0], w.ends[:0]
+		w.retryAt, w.backoff = time.Time{}, 0
+		return err
+	}
+	w.backoff *= 2
+	if w.backoff < retryMin {
+		w.backoff = retryMin
+	} else if w.backoff > retryMax {
+		w.backoff = retryMax
+	}
+	w.retryAt = now().Add(w.backoff)
+	return err
+}
+
+// trim drops the oldest points beyond maxBuffered. w.mu must be held.
+func (w *Writer) trim() {
+	n := len(w.ends) - w.maxBuffered
+	if n <= 0 {
+		return
+	}
+	off := w.ends[n-1]
+	w.buf = append(w.buf[:0], w.buf[off:]...)
+	w.ends = append(w.ends[:0], w.ends[n:]...)
+	for i := range w.ends {
+		w.ends[i] -= off
+	}
+	w.dropped += int64(n)
+}
+
+func (w *Writer) post(body []byte) error {
+	req, err := http.NewRequest("POST", w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	req.Header.Set("Accept", "application/json")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	e := &Error{StatusCode: resp.StatusCode}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(b, e) != nil || e.Message == "" {
+		e.Message = strings.TrimSpace(string(b))
+	}
+	return e
+}
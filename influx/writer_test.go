@@ -0,0 +1,214 @@
+package influx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer records the bodies written to it and replies with status.
+type fakeServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	bodies []string
+	status int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{status: http.StatusNoContent}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != "POST" || r.URL.Path != "/api/v2/write" || q.Get("org") != "home" ||
+			q.Get("bucket") != "adc" || q.Get("precision") != "ns" || r.Header.Get("Authorization") != "Token secret" {
+			t.Errorf("unexpected request: %s %s %v", r.Method, r.URL, r.Header)
+		}
+		b, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.bodies = append(s.bodies, string(b))
+		if s.status == http.StatusBadRequest {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(s.status)
+			w.Write([]byte(`{"code":"invalid","message":"unable to parse"}`))
+			return
+		}
+		w.WriteHeader(s.status)
+	}))
+	return s
+}
+
+func (s *fakeServer) got() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func (s *fakeServer) setStatus(status int) {
+	s.mu.Lock()
+	s.status = status
+	s.mu.Unlock()
+}
+
+func point(x int) Point {
+	return Point{Measurement: "m", Fields: map[string]interface{}{"x": x}}
+}
+
+func Test_Writer(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	w, err := NewWriter(Options{URL: s.URL, Org: "home", Bucket: "adc", Token: "secret", BatchSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing is posted until there's a batch.
+	if err := w.Write(point(1), point(2)); err != nil {
+		t.Fatal(err)
+	} else if n := len(s.got()); n != 0 {
+		t.Fatalf("exp = 0 posts, got = %d", n)
+	}
+	if err := w.Write(point(3)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A temporary error keeps the points for the next post.
+	s.setStatus(http.StatusServiceUnavailable)
+	w.Write(point(4))
+	if err := w.Flush(); err == nil || !err.(*Error).Temporary() {
+		t.Fatalf("exp = temporary error, got = %v", err)
+	}
+	s.setStatus(http.StatusNoContent)
+	w.Write(point(5))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"m x=1i\nm x=2i\nm x=3i\n", "m x=4i\n", "m x=4i\nm x=5i\n"}
+	got := s.got()
+	if len(got) != len(exp) {
+		t.Fatalf("exp = %q, got = %q", exp, got)
+	}
+	for i := range exp {
+		if got[i] != exp[i] {
+			t.Fatalf("exp = %q, got = %q", exp, got)
+		}
+	}
+
+	// A rejected batch is dropped.
+	w, _ = NewWriter(Options{URL: s.URL + "/api/v2/write", Org: "home", Bucket: "adc", Token: "secret"})
+	s.setStatus(http.StatusBadRequest)
+	w.Write(point(6))
+	err = w.Flush()
+	if e, ok := err.(*Error); !ok || e.Temporary() || e.Code != "invalid" {
+		t.Fatalf("exp = invalid error, got = %v", err)
+	} else if exp := "influx: write failed: 400 invalid: unable to parse"; e.Error() != exp {
+		t.Fatalf("exp = %s, got = %s", exp, e)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	} else if n := len(s.got()); n != 4 {
+		t.Fatalf("exp = 4 posts, got = %d", n)
+	} else if n := w.Dropped(); n != 1 {
+		t.Fatalf("exp = 1 dropped, got = %d", n)
+	}
+}
+
+func Test_WriterBackoff(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+	clock := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	w, err := NewWriter(Options{URL: s.URL, Org: "home", Bucket: "adc", Token: "secret", BatchSize: 1, MaxBuffered: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.setStatus(http.StatusServiceUnavailable)
+	if err := w.Write(point(1)); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Writes while backing off are buffered, not posted.
+	for i, step := range []struct {
+		after time.Duration
+		posts int
+	}{
+		{0, 1},
+		{time.Second, 2}, // Retried after 1s.
+		{time.Second, 2},
+		{time.Second, 3}, // Retried after 2s more.
+	} {
+		clock = clock.Add(step.after)
+		w.Write(point(i + 2))
+		if n := len(s.got()); n != step.posts {
+			t.Fatalf("exp = %d posts, got = %d", step.posts, n)
+		}
+	}
+
+	// Only the newest points are kept.
+	if n := w.Dropped(); n != 2 {
+		t.Fatalf("exp = 2 dropped, got = %d", n)
+	}
+	s.setStatus(http.StatusNoContent)
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	got := s.got()
+	if exp := "m x=3i\nm x=4i\nm x=5i\n"; got[len(got)-1] != exp {
+		t.Fatalf("exp = %q, got = %q", exp, got[len(got)-1])
+	}
+
+	// A successful post resets the backoff.
+	if err := w.Write(point(20)); err != nil {
+		t.Fatal(err)
+	} else if n := len(s.got()); n != len(got)+1 {
+		t.Fatalf("exp = %d posts, got = %d", len(got)+1, n)
+	}
+}
+
+func Test_WriterInterval(t *testing.T) {
+	s := newFakeServer(t)
+	defer s.Close()
+
+	w, err := NewWriter(Options{URL: s.URL, Org: "home", Bucket: "adc", Token: "secret", FlushInterval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	w.Write(point(1))
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.got()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for a flush")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := s.got()[0]; got != "m x=1i\n" {
+		t.Fatalf("exp = %q, got = %q", "m x=1i\n", got)
+	}
+}
+
+func Test_NewWriter(t *testing.T) {
+	for _, opts := range []Options{
+		{URL: "localhost:8086", Bucket: "b"},
+		{URL: "http://localhost:8086"},
+		{URL: "ftp://localhost", Bucket: "b"},
+	} {
+		if _, err := NewWriter(opts); err == nil {
+			t.Fatalf("expected error for %+v", opts)
+		}
+	}
+	w, err := NewWriter(Options{URL: "http://localhost:8086/custom/write?x=1", Bucket: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if !strings.HasPrefix(w.url, "http://localhost:8086/custom/write?") || !strings.Contains(w.url, "x=1") {
+		t.Fatalf("unexpected URL: %s", w.url)
+	}
+}
@@ -0,0 +1,174 @@
+package fakedev
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/io/i2c/driver"
+)
+
+// Bus is an I2C bus with devices at some addresses and, optionally, a
+// TCA9548A mux. It fails transactions that overlap, to catch missing bus
+// locking.
+type Bus struct {
+	mu     sync.Mutex
+	devs   map[int]*Device
+	mux    *Mux
+	active bool
+	closed map[int]bool
+}
+
+// NewBus returns a bus with a new device at each of addrs.
+func NewBus(addrs ...int) *Bus {
+	b := &Bus{devs: make(map[int]*Device), closed: make(map[int]bool)}
+	for _, addr := range addrs {
+		b.devs[addr] = New()
+	}
+	return b
+}
+
+// Device returns the device at addr, or nil if there isn't one.
+func (b *Bus) Device(addr int) *Device {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.devs[addr]
+}
+
+// AddMux adds a mux at addr and returns it.
+func (b *Bus) AddMux(addr int) *Mux {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mux = &Mux{addr: addr}
+	return b.mux
+}
+
+// Closed returns true if a connection to addr was closed.
+func (b *Bus) Closed(addr int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closed[addr]
+}
+
+// Open implements driver.Opener. Devices behind the mux are looked up by
+// each transaction, since they depend on the selected channels.
+func (b *Bus) Open(addr int, tenbit bool) (driver.Conn, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.devs[addr] == nil && b.mux == nil {
+		return nil, fmt.Errorf("no device at 0x%x", addr)
+	}
+	return &conn{bus: b, addr: addr}, nil
+}
+
+// conn is a connection to an address on a Bus.
+type conn struct {
+	bus  *Bus
+	addr int
+}
+
+func (c *conn) Tx(w, r []byte) error {
+	b := c.bus
+	b.mu.Lock()
+	if b.active {
+		b.mu.Unlock()
+		return fmt.Errorf("overlapping transaction at 0x%x", c.addr)
+	}
+	b.active = true
+	dev, mux := b.devs[c.addr], b.mux
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.active = false
+		b.mu.Unlock()
+	}()
+
+	if mux != nil {
+		if c.addr == mux.addr {
+			return mux.tx(w)
+		}
+		if d, err := mux.device(c.addr); err != nil {
+			return err
+		} else if d != nil {
+			if dev != nil {
+				return fmt.Errorf("address conflict at 0x%x", c.addr)
+			}
+			dev = d
+		}
+	}
+	if dev == nil {
+		return fmt.Errorf("no device at 0x%x", c.addr)
+	}
+	return dev.Tx(w, r)
+}
+
+func (c *conn) Close() error {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	c.bus.closed[c.addr] = true
+	return nil
+}
+
+// Mux is a TCA9548A. Devices on its downstream buses respond when their
+// channel is selected.
+type Mux struct {
+	mu      sync.Mutex
+	addr    int
+	sel     byte
+	selects int
+	chans   [8]*Bus
+}
+
+// SetChannel puts bus on downstream channel n.
+func (m *Mux) SetChannel(n int, bus *Bus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chans[n] = bus
+}
+
+// Selected returns the selected channels' mask.
+func (m *Mux) Selected() byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sel
+}
+
+// Selects returns the number of writes to the control register.
+func (m *Mux) Selects() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.selects
+}
+
+// tx writes the control register.
+func (m *Mux) tx(w []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(w) != 1 {
+		return fmt.Errorf("exp = 1 byte control write, got = %d", len(w))
+	}
+	m.sel = w[0]
+	m.selects++
+	return nil
+}
+
+// device returns the device at addr on a selected channel, or nil if there
+// isn't one.
+func (m *Mux) device(addr int) (*Device, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var dev *Device
+	for i, b := range m.chans {
+		if m.sel&(1<<uint(i)) == 0 || b == nil {
+			continue
+		}
+		d := b.Device(addr)
+		if d == nil {
+			continue
+		}
+		if dev != nil {
+			return nil, fmt.Errorf("address conflict at 0x%x", addr)
+		}
+		dev = d
+	}
+	return dev, nil
+}
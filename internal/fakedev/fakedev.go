@@ -0,0 +1,277 @@
+// Package fakedev is a fake ADS111x and I2C bus for tests.
+//
+// It can't import ads111x, whose tests use it, so it has its own copy of
+// the register addresses and config bits it needs.
+package fakedev
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/io/i2c/driver"
+)
+
+// Registers.
+const (
+	ConversionReg = 0
+	ConfigReg     = 1
+	LoThreshReg   = 2
+	HiThreshReg   = 3
+)
+
+// DefaultConfig is the config register's reset value.
+const DefaultConfig = 0x8583
+
+const (
+	statusMask = 0x8000
+	ainMask    = 0x7000
+	scaleMask  = 0x0e00
+	modeMask   = 0x0100
+	rateMask   = 0x00e0
+	single     = 0x0100
+)
+
+// fullScale are the full scale ranges by PGA code.
+var fullScale = [8]float64{6.144, 4.096, 2.048, 1.024, 0.512, 0.256, 0.256, 0.256}
+
+// samplesPerSecond are the data rates by DR code.
+var samplesPerSecond = [8]float64{8, 16, 32, 64, 128, 250, 475, 860}
+
+// Device is an ADS111x with a register pointer. Opened directly it's at
+// every address. It's safe for concurrent use.
+//
+// Writing the config with the status bit set in single-shot mode starts a
+// conversion, which takes the nominal conversion time. In continuous mode
+// conversions finish one after another from when the config was written,
+// and the device is always busy. The conversion register holds the last finished conversion, if there's
+// anything to convert; see SetInput, SetVolts and SetConvert.
+type Device struct {
+	mu      sync.Mutex
+	regs    [4]uint16
+	reg     byte
+	addr    int
+	fail    error
+	reads   int
+	now     func() time.Time
+	speed   float64
+	inputs  map[uint16]uint16
+	volts   map[uint16]float64
+	convert func(cfg uint16, t time.Time) uint16
+	// started is set while a single-shot conversion is in progress,
+	// changed is when the config was written, and done is when the last
+	// latched conversion finished.
+	started bool
+	changed time.Time
+	done    time.Time
+	// busyPolls is how many config reads report a conversion in progress
+	// after one finishes.
+	busyPolls int
+	busy      int
+}
+
+// New returns a device with the registers at their reset values.
+func New() *Device {
+	return &Device{
+		regs:  [4]uint16{ConfigReg: DefaultConfig, LoThreshReg: 0x8000, HiThreshReg: 0x7fff},
+		now:   time.Now,
+		speed: 1,
+	}
+}
+
+// Open implements driver.Opener.
+func (d *Device) Open(addr int, tenbit bool) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.addr = addr
+	return d, nil
+}
+
+// Tx implements driver.Conn.
+func (d *Device) Tx(w, r []byte) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.fail != nil {
+		return d.fail
+	}
+	if len(w) > 0 {
+		if w[0] > HiThreshReg {
+			return fmt.Errorf("invalid register %d", w[0])
+		}
+		d.reg = w[0]
+		if len(w) == 3 {
+			d.write(uint16(w[1])<<8 | uint16(w[2]))
+		}
+	}
+	if len(r) >= 2 {
+		v := d.read()
+		r[0], r[1] = byte(v>>8), byte(v)
+	}
+	return nil
+}
+
+// Close implements driver.Conn.
+func (d *Device) Close() error { return nil }
+
+// write writes v to the selected register. d.mu must be held.
+func (d *Device) write(v uint16) {
+	if d.reg != ConfigReg {
+		d.regs[d.reg] = v
+		return
+	}
+	d.update()
+	d.regs[ConfigReg] = v
+	d.changed = d.now()
+	d.started = v&modeMask == single && v&statusMask != 0
+	if d.started {
+		d.busy = d.busyPolls
+	}
+}
+
+// read returns the selected register. d.mu must be held.
+func (d *Device) read() uint16 {
+	d.update()
+	switch d.reg {
+	case ConversionReg:
+		d.reads++
+	case ConfigReg:
+		// The status bit reads as busy in continuous mode.
+		v := d.regs[ConfigReg] | statusMask
+		if v&modeMask != single || d.started {
+			v &^= statusMask
+		} else if d.busy > 0 {
+			v &^= statusMask
+			d.busy--
+		}
+		return v
+	}
+	return d.regs[d.reg]
+}
+
+// update latches the conversions finished by now. d.mu must be held.
+func (d *Device) update() {
+	cfg := d.regs[ConfigReg]
+	p := d.period(cfg)
+	if cfg&modeMask != single {
+		if n := d.now().Sub(d.changed) / p; n > 0 {
+			d.latch(cfg, d.changed.Add(n*p))
+		}
+	} else if d.started && d.now().Sub(d.changed) >= p {
+		d.latch(cfg, d.changed.Add(p))
+		d.started = false
+	}
+}
+
+// period returns how long a conversion with cfg takes.
+func (d *Device) period(cfg uint16) time.Duration {
+	ct := time.Duration(float64(time.Second) / samplesPerSecond[cfg&rateMask>>5])
+	return time.Duration(float64(ct) / d.speed)
+}
+
+// latch sets the conversion register to the conversion made with cfg that
+// finished at t, if there's anything to convert and it isn't already set.
+func (d *Device) latch(cfg uint16, t time.Time) {
+	if t.Equal(d.done) {
+		return
+	}
+	d.done = t
+	in := cfg & ainMask
+	switch {
+	case d.convert != nil:
+		d.regs[ConversionReg] = d.convert(cfg, t)
+	case d.volts != nil:
+		max := fullScale[cfg&scaleMask>>9]
+		cnt := math.Round(d.volts[in] / max * 32768)
+		cnt = math.Max(math.Min(cnt, math.MaxInt16), math.MinInt16)
+		d.regs[ConversionReg] = uint16(int16(cnt))
+	case d.inputs != nil:
+		d.regs[ConversionReg] = d.inputs[in]
+	}
+}
+
+// Reg returns a register's value as last written.
+func (d *Device) Reg(reg byte) uint16 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.regs[reg]
+}
+
+// SetReg sets a register.
+func (d *Device) SetReg(reg byte, v uint16) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.regs[reg] = v
+}
+
+// Addr returns the address the device was last opened at.
+func (d *Device) Addr() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.addr
+}
+
+// Reads returns the number of conversion register reads.
+func (d *Device) Reads() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.reads
+}
+
+// SetFail makes transactions fail with err, or succeed if it's nil.
+func (d *Device) SetFail(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.fail = err
+}
+
+// SetInput sets the conversion of input, the config's MUX bits, to cnt.
+func (d *Device) SetInput(input, cnt uint16) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.inputs == nil {
+		d.inputs = make(map[uint16]uint16)
+	}
+	d.inputs[input] = cnt
+}
+
+// SetVolts sets the voltage on input, the config's MUX bits. It's
+// converted with the scale selected when the conversion finishes.
+func (d *Device) SetVolts(input uint16, v float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.volts == nil {
+		d.volts = make(map[uint16]float64)
+	}
+	d.volts[input] = v
+}
+
+// SetConvert sets fn to return the conversion made with cfg that finished
+// at t. It's called with the device locked, so it must not use it.
+func (d *Device) SetConvert(fn func(cfg uint16, t time.Time) uint16) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.convert = fn
+}
+
+// SetClock sets the clock conversions are timed by.
+func (d *Device) SetClock(now func() time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.now = now
+}
+
+// SetSpeed makes conversions take the nominal time divided by speed.
+func (d *Device) SetSpeed(speed float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.speed = speed
+}
+
+// SetBusyPolls sets how many config reads report a conversion in progress
+// after one finishes.
+func (d *Device) SetBusyPolls(n int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.busyPolls = n
+}
@@ -0,0 +1,208 @@
+package ads111x
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Channel is a named input on one of a Manager's ADCs.
+type Channel struct {
+	Name  string
+	Addr  I2CAddress
+	Input AIN
+}
+
+// Reading is a conversion read from a channel.
+type Reading struct {
+	Channel
+	Time  time.Time
+	Raw   int16
+	Volts float64
+	// Scale is the full scale range the conversion was made with.
+	Scale Scale
+}
+
+// Manager manages up to four ADCs on one bus and reads them by channel
+// name. Its methods serialize access, so it's safe for concurrent use.
+type Manager struct {
+	mu       sync.Mutex
+	bus      *Bus
+	addrs    []I2CAddress
+	adcs     map[I2CAddress]*ADC
+	names    []string
+	channels map[string]Channel
+}
+
+// OpenManager opens the I2C bus device, e.g., /dev/i2c-1, and the ADCs at
+// the addresses.
+func OpenManager(dev string, addrs ...I2CAddress) (*Manager, error) {
+	bus, err := OpenBus(dev)
+	if err != nil {
+		return nil, err
+	}
+	m, err := NewManager(bus, addrs...)
+	if err != nil {
+		bus.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// NewManager opens the ADCs at the addresses on the bus. Each ADC's
+// single-ended inputs are named ch0, ch1, ... in address order, so four
+// ADCs give ch0 through ch15. Closing the manager closes the bus.
+func NewManager(bus *Bus, addrs ...I2CAddress) (*Manager, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("no ADC addresses")
+	}
+	m := &Manager{
+		bus:      bus,
+		adcs:     make(map[I2CAddress]*ADC),
+		channels: make(map[string]Channel),
+	}
+	for i, addr := range addrs {
+		if _, ok := m.adcs[addr]; ok {
+			m.closeADCs()
+			return nil, fmt.Errorf("duplicate address 0x%x", uint8(addr))
+		}
+		adc, err := bus.OpenADC(addr)
+		if err != nil {
+			m.closeADCs()
+			return nil, err
+		}
+		m.addrs = append(m.addrs, addr)
+		m.adcs[addr] = adc
+		for j, in := range []AIN{AIN_0_GND, AIN_1_GND, AIN_2_GND, AIN_3_GND} {
+			m.addChannel(Channel{Name: fmt.Sprintf("ch%d", i*4+j), Addr: addr, Input: in})
+		}
+	}
+	return m, nil
+}
+
+// AddChannel adds a named channel, e.g., a differential input pair.
+func (m *Manager) AddChannel(name string, addr I2CAddress, input AIN) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.adcs[addr]; !ok {
+		return fmt.Errorf("no ADC at address 0x%x", uint8(addr))
+	}
+	if _, ok := m.channels[name]; ok {
+		return fmt.Errorf("duplicate channel name %q", name)
+	}
+	m.addChannel(Channel{Name: name, Addr: addr, Input: input})
+	return nil
+}
+
+func (m *Manager) addChannel(ch Channel) {
+	m.names = append(m.names, ch.Name)
+	m.channels[ch.Name] = ch
+}
+
+// Channels returns the channels in the order they were added.
+func (m *Manager) Channels() []Channel {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	chs := make([]Channel, 0, len(m.names))
+	for _, name := range m.names {
+		chs = append(chs, m.channels[name])
+	}
+	return chs
+}
+
+// Addrs returns the addresses of the ADCs.
+func (m *Manager) Addrs() []I2CAddress {
+	return append([]I2CAddress(nil), m.addrs...)
+}
+
+// Do calls fn with the ADC at addr while holding the manager's lock, e.g.,
+// to change its config.
+func (m *Manager) Do(addr I2CAddress, fn func(adc *ADC) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	adc, ok := m.adcs[addr]
+	if !ok {
+		return fmt.Errorf("no ADC at address 0x%x", uint8(addr))
+	}
+	return fn(adc)
+}
+
+// Read reads a channel by name.
+func (m *Manager) Read(name string) (Reading, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ch, ok := m.channels[name]
+	if !ok {
+		return Reading{}, fmt.Errorf("unknown channel %q", name)
+	}
+	return m.read(ch)
+}
+
+// Scan reads the named channels, or every channel if none are named, in
+// order.
+func (m *Manager) Scan(names ...string) ([]Reading, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(names) == 0 {
+		names = m.names
+	}
+	rs := make([]Reading, 0, len(names))
+	for _, name := range names {
+		ch, ok := m.channels[name]
+		if !ok {
+			return rs, fmt.Errorf("unknown channel %q", name)
+		}
+		r, err := m.read(ch)
+		if err != nil {
+			return rs, fmt.Errorf("%s: %w", name, err)
+		}
+		rs = append(rs, r)
+	}
+	return rs, nil
+}
+
+// read converts a channel and waits for the conversion, so reading
+// channels back-to-back doesn't return the previous channel's conversion.
+// m.mu must be held.
+func (m *Manager) read(ch Channel) (Reading, error) {
+	adc := m.adcs[ch.Addr]
+	cfg, err := adc.Config()
+	if err != nil {
+		return Reading{}, err
+	}
+	cnt, _, err := adc.readAIN(cfg, ch.Input)
+	if err != nil {
+		return Reading{}, err
+	}
+	fs := Scale(cfg & Scale_Mask)
+	return Reading{
+		Channel: ch,
+		Time:    now(),
+		Raw:     int16(cnt),
+		Volts:   Volts(cnt, fs),
+		Scale:   fs,
+	}, nil
+}
+
+// Close closes the ADCs and the bus.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := m.closeADCs()
+	if berr := m.bus.Close(); err == nil {
+		err = berr
+	}
+	return err
+}
+
+// closeADCs closes the ADCs and returns the first error.
+func (m *Manager) closeADCs() error {
+	var first error
+	for _, addr := range m.addrs {
+		if err := m.adcs[addr].Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
@@ -0,0 +1,98 @@
+package ads111x
+
+import (
+	"sync"
+	"testing"
+)
+
+func Test_Manager(t *testing.T) {
+	addrs := []int{0x48, 0x49, 0x4a, 0x4b}
+	fb := newFakeBus(addrs...)
+	// Each input converts to a different value, so a reading of the
+	// previous input's conversion is caught.
+	inputs := make(map[int]map[AIN]uint16)
+	for _, addr := range addrs {
+		inputs[addr] = map[AIN]uint16{
+			AIN_0_GND: uint16(addr)<<8 | 0,
+			AIN_1_GND: uint16(addr)<<8 | 1,
+			AIN_2_GND: uint16(addr)<<8 | 2,
+			AIN_3_GND: uint16(addr)<<8 | 3,
+			AIN_0_1:   uint16(addr)<<8 | 4,
+		}
+	}
+	inputs[0x49][AIN_1_GND] = 0x4000
+	for addr, vals := range inputs {
+		for in, cnt := range vals {
+			fb.Device(addr).SetInput(uint16(in), cnt)
+		}
+	}
+	m, err := NewManager(NewBus(fb), Addr48, Addr49, Addr4A, Addr4B)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	chs := m.Channels()
+	if len(chs) != 16 {
+		t.Fatalf("exp = 16 channels, got = %d", len(chs))
+	}
+	if ch := chs[5]; ch.Name != "ch5" || ch.Addr != Addr49 || ch.Input != AIN_1_GND {
+		t.Fatalf("unexpected channel: %+v", ch)
+	}
+
+	r, err := m.Read("ch5")
+	if err != nil {
+		t.Fatal(err)
+	} else if r.Volts != 1.024 || r.Raw != 0x4000 || r.Scale != Scale_2_048V {
+		t.Fatalf("unexpected reading: %+v", r)
+	}
+	// Reading ch5 selected AIN_1_GND on the device at 0x49.
+	if in := AIN(fb.Device(0x49).Reg(ConfigReg) & AIN_Mask); in != AIN_1_GND {
+		t.Fatalf("exp = %v, got = %v", AIN_1_GND, in)
+	}
+
+	if err := m.AddChannel("bridge", Addr4A, AIN_0_1); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddChannel("bridge", Addr4A, AIN_2_3); err == nil {
+		t.Fatal("expected error for duplicate name")
+	}
+	if err := m.AddChannel("x", 0x40, AIN_2_3); err == nil {
+		t.Fatal("expected error for unknown address")
+	}
+	if _, err := m.Read("nope"); err == nil {
+		t.Fatal("expected error for unknown channel")
+	}
+
+	// Concurrent scans are serialized; the fake bus fails on overlapping
+	// transactions.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rs, err := m.Scan()
+			if err != nil {
+				errs <- err
+				return
+			} else if len(rs) != 17 {
+				t.Errorf("exp = 17 readings, got = %d", len(rs))
+			}
+			for _, r := range rs {
+				if exp := inputs[int(r.Addr)][r.Input]; uint16(r.Raw) != exp {
+					t.Errorf("%s: exp = 0x%x, got = 0x%x", r.Name, exp, uint16(r.Raw))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	if _, err := NewManager(NewBus(fb), Addr48, Addr48); err == nil {
+		t.Fatal("expected error for duplicate address")
+	}
+}
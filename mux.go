@@ -0,0 +1,158 @@
+package ads111x
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/exp/io/i2c/driver"
+)
+
+// MuxChannels is the number of downstream channels on a TCA9548A.
+const MuxChannels = 8
+
+// DefaultMuxAddr is the TCA9548A address with its address pins low. The
+// address pins select 0x70 through 0x77.
+const DefaultMuxAddr = 0x70
+
+// Mux is a TCA9548A or PCA9548A I2C multiplexer. Devices behind it are
+// opened from the bus returned by Channel. Before each transaction the
+// mux is switched to the device's channel, unless it's already selected.
+//
+// The mux must only be used through one Mux, or the cached selection will
+// be wrong.
+type Mux struct {
+	mu     sync.Mutex
+	parent driver.Opener
+	ctl    driver.Conn
+	sel    byte // selected channels
+	known  bool // sel matches the mux
+}
+
+// NewMux opens the mux at addr on the parent bus. The parent can be a Bus or
+// another mux's channel.
+func NewMux(parent driver.Opener, addr int) (*Mux, error) {
+	if addr < DefaultMuxAddr || addr > DefaultMuxAddr+7 {
+		return nil, fmt.Errorf("invalid mux address 0x%x", addr)
+	}
+	ctl, err := parent.Open(addr, false)
+	if err != nil {
+		return nil, err
+	}
+	return &Mux{parent: parent, ctl: ctl}, nil
+}
+
+// Channel returns the bus behind downstream channel n, 0 through 7.
+func (m *Mux) Channel(n int) (*Bus, error) {
+	if n < 0 || n >= MuxChannels {
+		return nil, fmt.Errorf("invalid mux channel %d", n)
+	}
+	return NewBus(&muxChannel{mux: m, mask: 1 << uint(n)}), nil
+}
+
+// Disable disconnects every downstream channel.
+func (m *Mux) Disable() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.selectChannels(0)
+}
+
+// Close closes the connection to the mux.
+func (m *Mux) Close() error {
+	return m.ctl.Close()
+}
+
+// selectChannels writes the channel mask to the mux control register if it
+// differs from the cached selection. m.mu must be held.
+func (m *Mux) selectChannels(mask byte) error {
+	if m.known && m.sel == mask {
+		return nil
+	}
+	if err := m.ctl.Tx([]byte{mask}, nil); err != nil {
+		m.known = false
+		return err
+	}
+	m.sel, m.known = mask, true
+	return nil
+}
+
+// muxChannel opens devices on one of a mux's downstream channels.
+type muxChannel struct {
+	mux  *Mux
+	mask byte
+}
+
+func (c *muxChannel) Open(addr int, tenbit bool) (driver.Conn, error) {
+	conn, err := c.mux.parent.Open(addr, tenbit)
+	if err != nil {
+		return nil, err
+	}
+	return &muxConn{ch: c, conn: conn}, nil
+}
+
+// muxConn is a connection whose transactions select its channel first.
+type muxConn struct {
+	ch   *muxChannel
+	conn driver.Conn
+}
+
+func (c *muxConn) Tx(w, r []byte) error {
+	m := c.ch.mux
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.selectChannels(c.ch.mask); err != nil {
+		return err
+	}
+	if err := c.conn.Tx(w, r); err != nil {
+		// The mux may have been reset; select it again next time.
+		m.known = false
+		return err
+	}
+	return nil
+}
+
+func (c *muxConn) Close() error {
+	return c.conn.Close()
+}
+
+// OpenMuxADC opens the I2C bus device, e.g., /dev/i2c-1, and the ADC at addr
+// on channel ch of the mux at muxAddr. Closing the ADC closes the bus.
+func OpenMuxADC(dev string, muxAddr, ch int, addr I2CAddress) (*ADC, error) {
+	bus, err := OpenBus(dev)
+	if err != nil {
+		return nil, err
+	}
+	mux, err := NewMux(bus, muxAddr)
+	if err != nil {
+		bus.Close()
+		return nil, err
+	}
+	cb, err := mux.Channel(ch)
+	if err != nil {
+		bus.Close()
+		return nil, err
+	}
+	adc, err := cb.OpenADC(addr)
+	if err != nil {
+		bus.Close()
+		return nil, err
+	}
+	adc.i2c = &ownedDevice{i2cdevice: adc.i2c, owned: []io.Closer{mux, bus}}
+	return adc, nil
+}
+
+// ownedDevice is a device that also closes what it was opened from.
+type ownedDevice struct {
+	i2cdevice
+	owned []io.Closer
+}
+
+func (d *ownedDevice) Close() error {
+	err := d.i2cdevice.Close()
+	for _, c := range d.owned {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
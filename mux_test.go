@@ -0,0 +1,69 @@
+package ads111x
+
+import "testing"
+
+func Test_Mux(t *testing.T) {
+	fb := newFakeBus()
+	fm := fb.AddMux(0x70)
+	ch5, ch2 := newFakeBus(0x48), newFakeBus(0x48)
+	ch5.Device(0x48).SetReg(ConversionReg, 0x4000)
+	ch2.Device(0x48).SetReg(ConversionReg, 0x2000)
+	fm.SetChannel(5, ch5)
+	fm.SetChannel(2, ch2)
+
+	mux, err := NewMux(NewBus(fb), 0x70)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mux.Close()
+
+	open := func(ch int) *ADC {
+		b, err := mux.Channel(ch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adc, err := b.OpenADC(Addr48)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return adc
+	}
+	adc5, adc2 := open(5), open(2)
+
+	read := func(adc *ADC, exp float64) {
+		if got, err := adc.ReadVolts(AIN_0_GND); err != nil {
+			t.Fatal(err)
+		} else if got != exp {
+			t.Fatalf("exp = %f, got = %f", exp, got)
+		}
+	}
+	read(adc5, 1.024)
+	n := fm.Selects()
+	read(adc5, 1.024)
+	// The selection is cached.
+	if got := fm.Selects(); got != n {
+		t.Fatalf("exp = %d selects, got = %d", n, got)
+	}
+	read(adc2, 0.512)
+	if got, sel := fm.Selects(), fm.Selected(); got != n+1 || sel != 1<<2 {
+		t.Fatalf("exp = %d selects of 0x04, got = %d of 0x%x", n+1, got, sel)
+	}
+
+	if err := mux.Disable(); err != nil {
+		t.Fatal(err)
+	} else if sel := fm.Selected(); sel != 0 {
+		t.Fatalf("exp = 0, got = 0x%x", sel)
+	}
+	// A device on a disabled channel doesn't respond.
+	if _, err := open(3).Config(); err == nil {
+		t.Fatal("expected error for missing device")
+	}
+	read(adc5, 1.024)
+
+	if _, err := mux.Channel(8); err == nil {
+		t.Fatal("expected error for invalid channel")
+	}
+	if _, err := NewMux(fb, 0x48); err == nil {
+		t.Fatal("expected error for invalid address")
+	}
+}
@@ -0,0 +1,62 @@
+package ads111x
+
+import "fmt"
+
+// Ratio is the result of a ratiometric measurement.
+type Ratio struct {
+	// Input is the signal input.
+	Input AIN
+	// Ratio is Signal / Reference.
+	Ratio float64
+	// Signal is the voltage on the signal input.
+	Signal float64
+	// Reference is the voltage on the reference input.
+	Reference float64
+}
+
+// ReadRatio reads the signal input and the reference input back-to-back with
+// the same scale and data rate and returns their ratio. For sensors excited
+// from the same supply as the reference, the ratio doesn't drift with the
+// supply voltage.
+func (adc *ADC) ReadRatio(signal, ref AIN) (Ratio, error) {
+	r, err := adc.ReadRatios(ref, signal)
+	if err != nil {
+		return Ratio{}, err
+	}
+	return r[0], nil
+}
+
+// ReadRatios reads the reference input once and then each signal input,
+// returning a ratio for each signal in the order given.
+func (adc *ADC) ReadRatios(ref AIN, signals ...AIN) ([]Ratio, error) {
+	cfg, err := adc.Config()
+	if err != nil {
+		return nil, err
+	}
+	fs := Scale(cfg & Scale_Mask)
+
+	cnt, cfg, err := adc.readAIN(cfg, ref)
+	if err != nil {
+		return nil, err
+	}
+	refVolts := Volts(cnt, fs)
+	if refVolts == 0 {
+		return nil, fmt.Errorf("reference input 0x%x reads 0 V", uint16(ref))
+	}
+
+	ratios := make([]Ratio, 0, len(signals))
+	for _, in := range signals {
+		cnt, cfg, err = adc.readAIN(cfg, in)
+		if err != nil {
+			return nil, err
+		}
+		v := Volts(cnt, fs)
+		ratios = append(ratios, Ratio{
+			Input:     in,
+			Ratio:     v / refVolts,
+			Signal:    v,
+			Reference: refVolts,
+		})
+	}
+	return ratios, nil
+}
@@ -0,0 +1,48 @@
+package ads111x
+
+import "testing"
+
+func Test_Volts(t *testing.T) {
+	test := func(cnt uint16, fs Scale, exp float64) {
+		if got := Volts(cnt, fs); got != exp {
+			t.Fatalf("exp = %f, got = %f", exp, got)
+		}
+	}
+	test(0x4000, Scale_2_048V, 1.024)
+	test(0xc000, Scale_2_048V, -1.024)
+	test(0x0000, Scale_0_256V, 0)
+}
+
+func Test_ReadRatios(t *testing.T) {
+	adc := newInputTestADC(map[AIN]uint16{
+		AIN_3_GND: 0x6000, // 3.072 V at +/- 4.096 V
+		AIN_0_GND: 0x3000,
+		AIN_1_GND: 0x1800,
+	})
+	defer mustClose(adc)
+	if err := adc.SetScale(Scale_4_096V); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := adc.ReadRatio(AIN_0_GND, AIN_3_GND)
+	if err != nil {
+		t.Fatal(err)
+	} else if r.Ratio != 0.5 || r.Signal != 1.536 || r.Reference != 3.072 {
+		t.Fatalf("exp = 0.5 (1.536 / 3.072), got = %v (%v / %v)", r.Ratio, r.Signal, r.Reference)
+	}
+
+	rs, err := adc.ReadRatios(AIN_3_GND, AIN_0_GND, AIN_1_GND)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(rs) != 2 || rs[0].Ratio != 0.5 || rs[1].Ratio != 0.25 || rs[1].Input != AIN_1_GND {
+		t.Fatalf("unexpected ratios: %+v", rs)
+	}
+
+	if _, err := adc.ReadRatio(AIN_0_GND, AIN_2_GND); err == nil {
+		t.Fatal("expected error for 0 V reference")
+	}
+}
+
+func bytesToUint16BE(b []byte) uint16 {
+	return uint16(b[0])<<8 | uint16(b[1])
+}
@@ -0,0 +1,327 @@
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+var _ ads111x.Device = (*Client)(nil)
+
+// Error is an error returned by the server.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("remote: %s", e.Message)
+}
+
+// Client is an ADC served by a Server. It implements ads111x.Device.
+type Client struct {
+	url string
+	hc  *http.Client
+	// own is whether hc was made by NewClient, so Close can close its idle
+	// connections.
+	own bool
+}
+
+// NewClient returns a client for the server at url, e.g.,
+// http://pi.local:8080. If hc is nil, it uses an http.Client of its own.
+func NewClient(url string, hc *http.Client) *Client {
+	c := &Client{url: strings.TrimSuffix(url, "/"), hc: hc}
+	if hc == nil {
+		c.hc = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+		c.own = true
+	}
+	return c
+}
+
+// Close closes idle connections to the server, if the client made its own
+// http.Client. A client given one leaves its connections to its owner.
+func (c *Client) Close() error {
+	if c.own {
+		c.hc.CloseIdleConnections()
+	}
+	return nil
+}
+
+// Read reads an input.
+func (c *Client) Read(input ads111x.AIN) (Reading, error) {
+	var r Reading
+	err := c.do("GET", "/v1/read?"+inputQuery(input).Encode(), nil, &r)
+	return r, err
+}
+
+// ReadVolts reads the voltage from the input.
+func (c *Client) ReadVolts(input ads111x.AIN) (float64, error) {
+	r, err := c.Read(input)
+	return r.Volts, err
+}
+
+// ReadAIN reads the conversion value from the input.
+func (c *Client) ReadAIN(input ads111x.AIN) (uint16, error) {
+	r, err := c.Read(input)
+	return uint16(r.Raw), err
+}
+
+// GetConfig returns the config register and its fields.
+func (c *Client) GetConfig() (Config, error) {
+	var cfg Config
+	err := c.do("GET", "/v1/config", nil, &cfg)
+	return cfg, err
+}
+
+// UpdateConfig sets the config register or fields, and returns the new
+// config.
+func (c *Client) UpdateConfig(u ConfigUpdate) (Config, error) {
+	var cfg Config
+	err := c.do("PATCH", "/v1/config", u, &cfg)
+	return cfg, err
+}
+
+// Config returns the config register.
+func (c *Client) Config() (uint16, error) {
+	cfg, err := c.GetConfig()
+	return cfg.Raw, err
+}
+
+// WriteConfig writes the config register.
+func (c *Client) WriteConfig(cfg uint16) error {
+	_, err := c.UpdateConfig(ConfigUpdate{Raw: &cfg})
+	return err
+}
+
+// field returns the config register masked to a field.
+func (c *Client) field(mask uint16) (uint16, error) {
+	cfg, err := c.Config()
+	return cfg & mask, err
+}
+
+// setField sets a field to v's text form.
+func (c *Client) setField(name string, v encoding.TextMarshaler) error {
+	b, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	_, err = c.UpdateConfig(ConfigUpdate{Fields: map[string]string{name: string(b)}})
+	return err
+}
+
+// Status returns the current status.
+func (c *Client) Status() (ads111x.Status, error) {
+	v, err := c.field(ads111x.Status_Mask)
+	return ads111x.Status(v), err
+}
+
+// Mode returns the mode config setting.
+func (c *Client) Mode() (ads111x.Mode, error) {
+	v, err := c.field(ads111x.Mode_Mask)
+	return ads111x.Mode(v), err
+}
+
+// SetMode sets the mode of operation.
+func (c *Client) SetMode(m ads111x.Mode) error { return c.setField("mode", m) }
+
+// Scale returns the full scale range config setting.
+func (c *Client) Scale() (ads111x.Scale, error) {
+	v, err := c.field(ads111x.Scale_Mask)
+	return ads111x.Scale(v), err
+}
+
+// SetScale sets the full scale range.
+func (c *Client) SetScale(fs ads111x.Scale) error { return c.setField("scale", fs) }
+
+// DataRate returns the data rate config setting.
+func (c *Client) DataRate() (ads111x.DataRate, error) {
+	v, err := c.field(ads111x.DataRate_Mask)
+	return ads111x.DataRate(v), err
+}
+
+// SetDataRate sets the data rate.
+func (c *Client) SetDataRate(dr ads111x.DataRate) error { return c.setField("rate", dr) }
+
+// ComparatorMode returns the comparator mode config setting.
+func (c *Client) ComparatorMode() (ads111x.ComparatorMode, error) {
+	v, err := c.field(ads111x.ComparatorMode_Mask)
+	return ads111x.ComparatorMode(v), err
+}
+
+// SetComparatorMode sets the comparator mode.
+func (c *Client) SetComparatorMode(cm ads111x.ComparatorMode) error {
+	return c.setField("comp-mode", cm)
+}
+
+// ComparatorPolarity returns the comparator polarity config setting.
+func (c *Client) ComparatorPolarity() (ads111x.ComparatorPolarity, error) {
+	v, err := c.field(ads111x.ComparatorPolarity_Mask)
+	return ads111x.ComparatorPolarity(v), err
+}
+
+// SetComparatorPolarity sets the comparator polarity.
+func (c *Client) SetComparatorPolarity(cp ads111x.ComparatorPolarity) error {
+	return c.setField("comp-polarity", cp)
+}
+
+// ComparatorLatching returns the comparator latching config setting.
+func (c *Client) ComparatorLatching() (ads111x.ComparatorLatching, error) {
+	v, err := c.field(ads111x.ComparatorLatching_Mask)
+	return ads111x.ComparatorLatching(v), err
+}
+
+// SetComparatorLatching sets the comparator latching.
+func (c *Client) SetComparatorLatching(cl ads111x.ComparatorLatching) error {
+	return c.setField("comp-latch", cl)
+}
+
+// ComparatorQueue returns the comparator queue config setting.
+func (c *Client) ComparatorQueue() (ads111x.ComparatorQueue, error) {
+	v, err := c.field(ads111x.ComparatorQueue_Mask)
+	return ads111x.ComparatorQueue(v), err
+}
+
+// SetComparatorQueue sets the comparator queue.
+func (c *Client) SetComparatorQueue(cq ads111x.ComparatorQueue) error {
+	return c.setField("comp-queue", cq)
+}
+
+// Thresholds returns the comparator thresholds.
+func (c *Client) Thresholds() (lo, hi int16, err error) {
+	var t Thresholds
+	err = c.do("GET", "/v1/thresholds", nil, &t)
+	return t.Lo, t.Hi, err
+}
+
+// SetThresholds sets the comparator thresholds.
+func (c *Client) SetThresholds(lo, hi int16) error {
+	return c.do("PUT", "/v1/thresholds", Thresholds{Lo: lo, Hi: hi}, nil)
+}
+
+// do sends a request with in as the JSON body, if it's not nil, and decodes
+// the response into out.
+func (c *Client) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, c.url+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// responseError returns the error in a response.
+func responseError(resp *http.Response) error {
+	var e errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Error == "" {
+		e.Error = resp.Status
+	}
+	return &Error{StatusCode: resp.StatusCode, Message: e.Error}
+}
+
+func inputQuery(inputs ...ads111x.AIN) url.Values {
+	q := make(url.Values)
+	for _, in := range inputs {
+		b, _ := in.MarshalText()
+		q.Add("input", string(b))
+	}
+	return q
+}
+
+// Stream is a stream of readings from a server.
+type Stream struct {
+	body io.ReadCloser
+	sc   *bufio.Scanner
+}
+
+// Stream reads the inputs every interval until the stream is closed or
+// ctx is done. If n isn't 0, the stream ends after n rounds of readings.
+func (c *Client) Stream(ctx context.Context, interval time.Duration, n int, inputs ...ads111x.AIN) (*Stream, error) {
+	q := inputQuery(inputs...)
+	q.Set("interval", interval.String())
+	if n > 0 {
+		q.Set("n", fmt.Sprint(n))
+	}
+	req, err := http.NewRequest("GET", c.url+"/v1/stream?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return &Stream{body: resp.Body, sc: bufio.NewScanner(resp.Body)}, nil
+}
+
+// Next returns the next reading. It returns io.EOF at the end of the
+// stream, and the server's error if a read failed.
+func (s *Stream) Next() (Reading, error) {
+	var event string
+	var data []byte
+	for s.sc.Scan() {
+		line := s.sc.Text()
+		switch {
+		case line == "":
+			if data == nil {
+				continue
+			}
+			if event == "error" {
+				var e errorResponse
+				json.Unmarshal(data, &e)
+				return Reading{}, &Error{StatusCode: http.StatusInternalServerError, Message: e.Error}
+			}
+			var r Reading
+			err := json.Unmarshal(data, &r)
+			return r, err
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(line, "data:"))...)
+		}
+	}
+	if err := s.sc.Err(); err != nil {
+		return Reading{}, err
+	}
+	return Reading{}, io.EOF
+}
+
+// Close closes the stream.
+func (s *Stream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// readScaled is application code written against the interface.
+func readScaled(d ads111x.Device, fs ads111x.Scale, input ads111x.AIN) (float64, error) {
+	if err := d.SetScale(fs); err != nil {
+		return 0, err
+	}
+	return d.ReadVolts(input)
+}
+
+func Test_Client(t *testing.T) {
+	dev, srv := newTestServer(t)
+	defer srv.Close()
+	dev.SetReg(ads111x.ConversionReg, 0x4000)
+
+	c := NewClient(srv.URL+"/", nil)
+	defer c.Close()
+	v, err := readScaled(c, ads111x.Scale_1_024V, ads111x.AIN_2_3)
+	if err != nil {
+		t.Fatal(err)
+	} else if v != 0.512 {
+		t.Fatalf("exp = 0.512, got = %v", v)
+	}
+	if in := ads111x.AIN(dev.Reg(ads111x.ConfigReg) & ads111x.AIN_Mask); in != ads111x.AIN_2_3 {
+		t.Fatalf("exp = %v, got = %v", ads111x.AIN_2_3, in)
+	}
+	if raw, err := c.ReadAIN(ads111x.AIN_2_3); err != nil || raw != 0x4000 {
+		t.Fatalf("exp = 0x4000, got = 0x%x, %v", raw, err)
+	}
+
+	if err := c.SetDataRate(ads111x.DR_16SPS); err != nil {
+		t.Fatal(err)
+	} else if dr, err := c.DataRate(); err != nil || dr != ads111x.DR_16SPS {
+		t.Fatalf("exp = %v, got = %v, %v", ads111x.DR_16SPS, dr, err)
+	}
+	if err := c.SetComparatorQueue(ads111x.AfterTwo); err != nil {
+		t.Fatal(err)
+	} else if cq, err := c.ComparatorQueue(); err != nil || cq != ads111x.AfterTwo {
+		t.Fatalf("exp = %v, got = %v, %v", ads111x.AfterTwo, cq, err)
+	}
+	if err := c.WriteConfig(ads111x.DefaultConfig &^ ads111x.Status_Mask); err != nil {
+		t.Fatal(err)
+	} else if cfg, err := c.GetConfig(); err != nil || cfg.Raw != ads111x.DefaultConfig || cfg.Fields["comp-queue"] != "disable" {
+		t.Fatalf("unexpected config: %+v, %v", cfg, err)
+	}
+	if err := c.SetThresholds(-5, 5); err != nil {
+		t.Fatal(err)
+	} else if lo, hi, err := c.Thresholds(); err != nil || lo != -5 || hi != 5 {
+		t.Fatalf("exp = -5, 5, got = %d, %d, %v", lo, hi, err)
+	}
+
+	// Invalid values fail before they're sent.
+	if err := c.SetScale(ads111x.Scale(7 << ads111x.Scale_LSB)); err == nil {
+		t.Fatal("expected error")
+	}
+	dev.SetFail(errors.New("remote I/O error"))
+	_, err = c.ReadVolts(ads111x.AIN_0_GND)
+	if e, ok := err.(*Error); !ok || e.StatusCode != 500 || e.Error() != "remote: remote I/O error" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func Test_ClientStream(t *testing.T) {
+	dev, srv := newTestServer(t)
+	defer srv.Close()
+	dev.SetReg(ads111x.ConversionReg, 0x2000)
+	c := NewClient(srv.URL, nil)
+
+	s, err := c.Stream(context.Background(), time.Millisecond, 2, ads111x.AIN_0_GND, ads111x.AIN_3_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	exp := []ads111x.AIN{ads111x.AIN_0_GND, ads111x.AIN_3_GND, ads111x.AIN_0_GND, ads111x.AIN_3_GND}
+	for _, in := range exp {
+		r, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if r.Input != in || r.Volts != 0.512 {
+			t.Fatalf("unexpected reading: %+v", r)
+		}
+	}
+	if _, err := s.Next(); err != io.EOF {
+		t.Fatalf("exp = EOF, got = %v", err)
+	}
+
+	// An unbounded stream ends when its context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err = c.Stream(ctx, time.Millisecond, 0, ads111x.AIN_0_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Next(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		_, err = s.Next()
+	}
+	s.Close()
+
+	if _, err := c.Stream(context.Background(), time.Microsecond, 0, ads111x.AIN_0_GND); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func Test_ClientClose(t *testing.T) {
+	// Close leaves the connections of a client it was given alone.
+	tr := &closeTransport{RoundTripper: http.DefaultTransport}
+	if err := NewClient("http://pi.local:8080", &http.Client{Transport: tr}).Close(); err != nil {
+		t.Fatal(err)
+	} else if tr.closed {
+		t.Fatal("closed idle connections of a client it doesn't own")
+	}
+	if c := NewClient("http://pi.local:8080", nil); c.hc == http.DefaultClient {
+		t.Fatal("exp = own client, got = http.DefaultClient")
+	}
+}
+
+// closeTransport records whether its idle connections were closed.
+type closeTransport struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (t *closeTransport) CloseIdleConnections() { t.closed = true }
@@ -0,0 +1,301 @@
+// Package remote serves an ADC over HTTP/JSON and provides a client that
+// implements ads111x.Device, so code that uses an ADC works the same
+// whether the ADC is local or on another machine.
+//
+// The server's endpoints are:
+//
+//	GET   /v1/read?input=ain0-gnd      read an input
+//	GET   /v1/config                   get the config register and fields
+//	PATCH /v1/config                   set the register or some fields
+//	GET   /v1/thresholds               get the comparator thresholds
+//	PUT   /v1/thresholds               set the comparator thresholds
+//	GET   /v1/stream?input=ain0-gnd&interval=100ms[&n=count]
+//	                                   read inputs repeatedly, as
+//	                                   Server-Sent Events
+//
+// Errors are returned as {"error": "message"}.
+package remote
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// MinStreamInterval is the shortest interval a stream can ask for.
+const MinStreamInterval = time.Millisecond
+
+// now is for test purposes.
+var now = time.Now
+
+// Reading is a conversion read from an input.
+type Reading struct {
+	Input ads111x.AIN `json:"input"`
+	Raw   int16       `json:"raw"`
+	Volts float64     `json:"volts"`
+	Time  time.Time   `json:"time"`
+}
+
+// Config is the config register, and its fields in their text forms, e.g.,
+// "scale": "2.048V".
+type Config struct {
+	Raw    uint16            `json:"raw"`
+	Fields map[string]string `json:"fields"`
+}
+
+// ConfigUpdate sets the config register, if Raw is set, and then the
+// fields.
+type ConfigUpdate struct {
+	Raw    *uint16           `json:"raw,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Thresholds are the comparator thresholds, as conversion values.
+type Thresholds struct {
+	Lo int16 `json:"lo"`
+	Hi int16 `json:"hi"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server serves an ADC over HTTP. Requests are serialized, so the ADC is
+// only used by one at a time.
+type Server struct {
+	mu  sync.Mutex
+	adc ads111x.Device
+	mux *http.ServeMux
+}
+
+// NewServer returns a server for the ADC.
+func NewServer(adc ads111x.Device) *Server {
+	s := &Server{adc: adc, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/read", s.handleRead)
+	s.mux.HandleFunc("/v1/config", s.handleConfig)
+	s.mux.HandleFunc("/v1/thresholds", s.handleThresholds)
+	s.mux.HandleFunc("/v1/stream", s.handleStream)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleRead(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, "GET") {
+		return
+	}
+	inputs, err := parseInputs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if len(inputs) != 1 {
+		writeError(w, http.StatusBadRequest, errors.New("read takes one input"))
+		return
+	}
+	rd, err := s.read(inputs[0])
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, rd)
+}
+
+// read converts an input with the ADC's current scale and waits for the
+// conversion, so streamed inputs don't read each other's.
+func (s *Server) read(input ads111x.AIN) (Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, err := s.adc.Scale()
+	if err != nil {
+		return Reading{}, err
+	}
+	cnt, err := s.adc.ReadAIN(input)
+	if err != nil {
+		return Reading{}, err
+	}
+	return Reading{Input: input, Raw: int16(cnt), Volts: ads111x.Volts(cnt, fs), Time: now()}, nil
+}
+
+func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, "GET", "PATCH") {
+		return
+	}
+	var u ConfigUpdate
+	if r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, err := s.adc.Config()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if r.Method == "PATCH" {
+		want := cfg
+		if u.Raw != nil {
+			want = *u.Raw
+		}
+		for name, value := range u.Fields {
+			if want, err = ads111x.SetFieldText(want, name, value); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+		if err := s.adc.WriteConfig(want); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if cfg, err = s.adc.Config(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	writeJSON(w, Config{Raw: cfg, Fields: ads111x.ConfigText(cfg)})
+}
+
+func (s *Server) handleThresholds(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, "GET", "PUT") {
+		return
+	}
+	var t Thresholds
+	if r.Method == "PUT" {
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.Method == "PUT" {
+		if err := s.adc.SetThresholds(t.Lo, t.Hi); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	lo, hi, err := s.adc.Thresholds()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, Thresholds{Lo: lo, Hi: hi})
+}
+
+// handleStream sends a reading event for each input every interval, until
+// the client goes away, n rounds have been sent or a read fails. A failed
+// read is sent as an error event.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, "GET") {
+		return
+	}
+	inputs, err := parseInputs(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	} else if len(inputs) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("no inputs"))
+		return
+	}
+	q := r.URL.Query()
+	interval := time.Second
+	if v := q.Get("interval"); v != "" {
+		if interval, err = time.ParseDuration(v); err != nil || interval < MinStreamInterval {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid interval %q, must be at least %v", v, MinStreamInterval))
+			return
+		}
+	}
+	n := 0
+	if v := q.Get("n"); v != "" {
+		if n, err = strconv.Atoi(v); err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid n %q", v))
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for i := 0; n == 0 || i < n; i++ {
+		if i > 0 {
+			select {
+			case <-t.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		for _, input := range inputs {
+			rd, err := s.read(input)
+			if err != nil {
+				writeEvent(w, "error", errorResponse{err.Error()})
+				flusher.Flush()
+				return
+			}
+			writeEvent(w, "reading", rd)
+		}
+		flusher.Flush()
+	}
+}
+
+// parseInputs parses the input query parameters.
+func parseInputs(r *http.Request) ([]ads111x.AIN, error) {
+	var inputs []ads111x.AIN
+	for _, v := range r.URL.Query()["input"] {
+		for _, s := range strings.Split(v, ",") {
+			var in ads111x.AIN
+			if err := in.Set(s); err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, in)
+		}
+	}
+	return inputs, nil
+}
+
+// allow writes a 405 response if the request's method isn't one of
+// methods.
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{err.Error()})
+}
+
+func writeEvent(w http.ResponseWriter, event string, v interface{}) {
+	b, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+}
@@ -0,0 +1,113 @@
+package remote
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Server(t *testing.T) {
+	dev, srv := newTestServer(t)
+	defer srv.Close()
+	dev.SetReg(ads111x.ConversionReg, 0x4000)
+
+	test := func(status int, exp, method, path, body string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != status {
+			t.Fatalf("exp = %d, got = %d: %s", status, resp.StatusCode, b)
+		} else if got := string(b); !strings.Contains(got, exp) {
+			t.Fatalf("exp = %q, got = %q", exp, got)
+		}
+	}
+
+	test(200, `{"input":"ain1-gnd","raw":16384,"volts":1.024,"time":"2026-10-19T12:00:00Z"}`, "GET", "/v1/read?input=ain1", "")
+	test(200, `"scale":"2.048V"`, "GET", "/v1/config", "")
+	test(200, `"scale":"4.096V"`, "PATCH", "/v1/config", `{"fields": {"scale": "4.096V", "rate": "860"}}`)
+	if cfg := dev.Reg(ads111x.ConfigReg); ads111x.DataRate(cfg&ads111x.DataRate_Mask) != ads111x.DR_860SPS {
+		t.Fatalf("unexpected config: 0x%x", cfg)
+	}
+	test(200, `"volts":2.048`, "GET", "/v1/read?input=ain1-gnd", "")
+	// Writing the status bit would start a conversion. It reads back as
+	// idle.
+	test(200, `"raw":34179`, "PATCH", "/v1/config", `{"raw": 1411}`)
+	test(200, `{"lo":-100,"hi":100}`, "PUT", "/v1/thresholds", `{"lo": -100, "hi": 100}`)
+	test(200, `{"lo":-100,"hi":100}`, "GET", "/v1/thresholds", "")
+
+	test(400, `{"error":"invalid AIN \"ain9\"`, "GET", "/v1/read?input=ain9", "")
+	test(400, "read takes one input", "GET", "/v1/read", "")
+	test(400, "status is read-only", "PATCH", "/v1/config", `{"fields": {"status": "busy"}}`)
+	test(400, `unknown field \"gain\"`, "PATCH", "/v1/config", `{"fields": {"gain": "2"}}`)
+	test(400, "invalid interval", "GET", "/v1/stream?input=ain0&interval=1us", "")
+	test(400, "no inputs", "GET", "/v1/stream", "")
+	test(405, "method DELETE not allowed", "DELETE", "/v1/config", "")
+
+	dev.SetFail(errors.New("remote I/O error"))
+	test(500, "remote I/O error", "GET", "/v1/read?input=ain0", "")
+}
+
+func Test_ServerStream(t *testing.T) {
+	dev, srv := newTestServer(t)
+	defer srv.Close()
+	dev.SetInput(uint16(ads111x.AIN_0_GND), 0x1000)
+	dev.SetInput(uint16(ads111x.AIN_1_GND), 0x2000)
+
+	resp, err := http.Get(srv.URL + "/v1/stream?input=ain0,ain1&interval=1ms&n=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("exp = text/event-stream, got = %s", ct)
+	}
+	if n := strings.Count(string(b), "event: reading\ndata: {"); n != 4 {
+		t.Fatalf("exp = 4 readings, got = %d: %s", n, b)
+	}
+	// Each reading is a conversion of its own input.
+	var raws []string
+	for _, ev := range strings.Split(string(b), "\n\n") {
+		if i := strings.Index(ev, `"raw":`); i >= 0 {
+			raws = append(raws, ev[i+6:i+6+strings.IndexByte(ev[i+6:], ',')])
+		}
+	}
+	if exp := "4096 8192 4096 8192"; strings.Join(raws, " ") != exp {
+		t.Fatalf("exp = %s, got = %s", exp, strings.Join(raws, " "))
+	}
+
+	dev.SetFail(errors.New("remote I/O error"))
+	resp, err = http.Get(srv.URL + "/v1/stream?input=ain0&interval=1ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if exp := "event: error\ndata: {\"error\":\"remote I/O error\"}\n\n"; string(b) != exp {
+		t.Fatalf("exp = %q, got = %q", exp, b)
+	}
+}
+
+// newTestServer returns a server for an ADC on a fake device.
+func newTestServer(t *testing.T) (*fakedev.Device, *httptest.Server) {
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+	dev := fakedev.New()
+	adc, err := ads111x.NewBus(dev).OpenADC(ads111x.Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dev, httptest.NewServer(NewServer(adc))
+}
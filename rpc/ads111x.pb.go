@@ -0,0 +1,638 @@
+// The ADC service serves an ADS111x analog to digital converter. The Go
+// code in ads111x.pb.go and ads111x_grpc.pb.go is generated from this file
+// by go generate, with protoc, protoc-gen-go and protoc-gen-go-grpc.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: ads111x.proto
+
+package rpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Input is an input selection. Its values are the config register's MUX
+// field plus one.
+type Input int32
+
+const (
+	Input_INPUT_UNSPECIFIED Input = 0
+	Input_INPUT_AIN0_AIN1   Input = 1
+	Input_INPUT_AIN0_AIN3   Input = 2
+	Input_INPUT_AIN1_AIN3   Input = 3
+	Input_INPUT_AIN2_AIN3   Input = 4
+	Input_INPUT_AIN0_GND    Input = 5
+	Input_INPUT_AIN1_GND    Input = 6
+	Input_INPUT_AIN2_GND    Input = 7
+	Input_INPUT_AIN3_GND    Input = 8
+)
+
+// Enum value maps for Input.
+var (
+	Input_name = map[int32]string{
+		0: "INPUT_UNSPECIFIED",
+		1: "INPUT_AIN0_AIN1",
+		2: "INPUT_AIN0_AIN3",
+		3: "INPUT_AIN1_AIN3",
+		4: "INPUT_AIN2_AIN3",
+		5: "INPUT_AIN0_GND",
+		6: "INPUT_AIN1_GND",
+		7: "INPUT_AIN2_GND",
+		8: "INPUT_AIN3_GND",
+	}
+	Input_value = map[string]int32{
+		"INPUT_UNSPECIFIED": 0,
+		"INPUT_AIN0_AIN1":   1,
+		"INPUT_AIN0_AIN3":   2,
+		"INPUT_AIN1_AIN3":   3,
+		"INPUT_AIN2_AIN3":   4,
+		"INPUT_AIN0_GND":    5,
+		"INPUT_AIN1_GND":    6,
+		"INPUT_AIN2_GND":    7,
+		"INPUT_AIN3_GND":    8,
+	}
+)
+
+func (x Input) Enum() *Input {
+	p := new(Input)
+	*p = x
+	return p
+}
+
+func (x Input) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Input) Descriptor() protoreflect.EnumDescriptor {
+	return file_ads111x_proto_enumTypes[0].Descriptor()
+}
+
+func (Input) Type() protoreflect.EnumType {
+	return &file_ads111x_proto_enumTypes[0]
+}
+
+func (x Input) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Input.Descriptor instead.
+func (Input) EnumDescriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{0}
+}
+
+type ReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Input         Input                  `protobuf:"varint,1,opt,name=input,proto3,enum=ads111x.v1.Input" json:"input,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadRequest) Reset() {
+	*x = ReadRequest{}
+	mi := &file_ads111x_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadRequest) ProtoMessage() {}
+
+func (x *ReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadRequest.ProtoReflect.Descriptor instead.
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ReadRequest) GetInput() Input {
+	if x != nil {
+		return x.Input
+	}
+	return Input_INPUT_UNSPECIFIED
+}
+
+type Reading struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Input         Input                  `protobuf:"varint,1,opt,name=input,proto3,enum=ads111x.v1.Input" json:"input,omitempty"`
+	Raw           int32                  `protobuf:"varint,2,opt,name=raw,proto3" json:"raw,omitempty"`
+	Volts         float64                `protobuf:"fixed64,3,opt,name=volts,proto3" json:"volts,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reading) Reset() {
+	*x = Reading{}
+	mi := &file_ads111x_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reading) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reading) ProtoMessage() {}
+
+func (x *Reading) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reading.ProtoReflect.Descriptor instead.
+func (*Reading) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Reading) GetInput() Input {
+	if x != nil {
+		return x.Input
+	}
+	return Input_INPUT_UNSPECIFIED
+}
+
+func (x *Reading) GetRaw() int32 {
+	if x != nil {
+		return x.Raw
+	}
+	return 0
+}
+
+func (x *Reading) GetVolts() float64 {
+	if x != nil {
+		return x.Volts
+	}
+	return 0
+}
+
+func (x *Reading) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type GetConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetConfigRequest) Reset() {
+	*x = GetConfigRequest{}
+	mi := &file_ads111x_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetConfigRequest) ProtoMessage() {}
+
+func (x *GetConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetConfigRequest) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{2}
+}
+
+// Config is the config register, and its fields in their text forms, e.g.,
+// "scale": "2.048V".
+type Config struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Raw           uint32                 `protobuf:"varint,1,opt,name=raw,proto3" json:"raw,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_ads111x_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Config) GetRaw() uint32 {
+	if x != nil {
+		return x.Raw
+	}
+	return 0
+}
+
+func (x *Config) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type UpdateConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Raw           *uint32                `protobuf:"varint,1,opt,name=raw,proto3,oneof" json:"raw,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConfigRequest) Reset() {
+	*x = UpdateConfigRequest{}
+	mi := &file_ads111x_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConfigRequest) ProtoMessage() {}
+
+func (x *UpdateConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConfigRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConfigRequest) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateConfigRequest) GetRaw() uint32 {
+	if x != nil && x.Raw != nil {
+		return *x.Raw
+	}
+	return 0
+}
+
+func (x *UpdateConfigRequest) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type GetThresholdsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThresholdsRequest) Reset() {
+	*x = GetThresholdsRequest{}
+	mi := &file_ads111x_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThresholdsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThresholdsRequest) ProtoMessage() {}
+
+func (x *GetThresholdsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThresholdsRequest.ProtoReflect.Descriptor instead.
+func (*GetThresholdsRequest) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{5}
+}
+
+// Thresholds are the comparator thresholds, as conversion values.
+type Thresholds struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lo            int32                  `protobuf:"varint,1,opt,name=lo,proto3" json:"lo,omitempty"`
+	Hi            int32                  `protobuf:"varint,2,opt,name=hi,proto3" json:"hi,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Thresholds) Reset() {
+	*x = Thresholds{}
+	mi := &file_ads111x_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Thresholds) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Thresholds) ProtoMessage() {}
+
+func (x *Thresholds) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Thresholds.ProtoReflect.Descriptor instead.
+func (*Thresholds) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Thresholds) GetLo() int32 {
+	if x != nil {
+		return x.Lo
+	}
+	return 0
+}
+
+func (x *Thresholds) GetHi() int32 {
+	if x != nil {
+		return x.Hi
+	}
+	return 0
+}
+
+type StreamRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Inputs   []Input                `protobuf:"varint,1,rep,packed,name=inputs,proto3,enum=ads111x.v1.Input" json:"inputs,omitempty"`
+	Interval *durationpb.Duration   `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// count is the number of rounds of readings to send, or 0 to send them
+	// until the call is canceled.
+	Count         uint32 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	mi := &file_ads111x_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ads111x_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_ads111x_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamRequest) GetInputs() []Input {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *StreamRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+var File_ads111x_proto protoreflect.FileDescriptor
+
+const file_ads111x_proto_rawDesc = "" +
+	"\n" +
+	"\rads111x.proto\x12\n" +
+	"ads111x.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"6\n" +
+	"\vReadRequest\x12'\n" +
+	"\x05input\x18\x01 \x01(\x0e2\x11.ads111x.v1.InputR\x05input\"\x8a\x01\n" +
+	"\aReading\x12'\n" +
+	"\x05input\x18\x01 \x01(\x0e2\x11.ads111x.v1.InputR\x05input\x12\x10\n" +
+	"\x03raw\x18\x02 \x01(\x05R\x03raw\x12\x14\n" +
+	"\x05volts\x18\x03 \x01(\x01R\x05volts\x12.\n" +
+	"\x04time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x12\n" +
+	"\x10GetConfigRequest\"\x8d\x01\n" +
+	"\x06Config\x12\x10\n" +
+	"\x03raw\x18\x01 \x01(\rR\x03raw\x126\n" +
+	"\x06fields\x18\x02 \x03(\v2\x1e.ads111x.v1.Config.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb4\x01\n" +
+	"\x13UpdateConfigRequest\x12\x15\n" +
+	"\x03raw\x18\x01 \x01(\rH\x00R\x03raw\x88\x01\x01\x12C\n" +
+	"\x06fields\x18\x02 \x03(\v2+.ads111x.v1.UpdateConfigRequest.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x06\n" +
+	"\x04_raw\"\x16\n" +
+	"\x14GetThresholdsRequest\",\n" +
+	"\n" +
+	"Thresholds\x12\x0e\n" +
+	"\x02lo\x18\x01 \x01(\x05R\x02lo\x12\x0e\n" +
+	"\x02hi\x18\x02 \x01(\x05R\x02hi\"\x87\x01\n" +
+	"\rStreamRequest\x12)\n" +
+	"\x06inputs\x18\x01 \x03(\x0e2\x11.ads111x.v1.InputR\x06inputs\x125\n" +
+	"\binterval\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count*\xc2\x01\n" +
+	"\x05Input\x12\x15\n" +
+	"\x11INPUT_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fINPUT_AIN0_AIN1\x10\x01\x12\x13\n" +
+	"\x0fINPUT_AIN0_AIN3\x10\x02\x12\x13\n" +
+	"\x0fINPUT_AIN1_AIN3\x10\x03\x12\x13\n" +
+	"\x0fINPUT_AIN2_AIN3\x10\x04\x12\x12\n" +
+	"\x0eINPUT_AIN0_GND\x10\x05\x12\x12\n" +
+	"\x0eINPUT_AIN1_GND\x10\x06\x12\x12\n" +
+	"\x0eINPUT_AIN2_GND\x10\a\x12\x12\n" +
+	"\x0eINPUT_AIN3_GND\x10\b2\x87\x03\n" +
+	"\x03ADC\x124\n" +
+	"\x04Read\x12\x17.ads111x.v1.ReadRequest\x1a\x13.ads111x.v1.Reading\x12=\n" +
+	"\tGetConfig\x12\x1c.ads111x.v1.GetConfigRequest\x1a\x12.ads111x.v1.Config\x12C\n" +
+	"\fUpdateConfig\x12\x1f.ads111x.v1.UpdateConfigRequest\x1a\x12.ads111x.v1.Config\x12I\n" +
+	"\rGetThresholds\x12 .ads111x.v1.GetThresholdsRequest\x1a\x16.ads111x.v1.Thresholds\x12?\n" +
+	"\rSetThresholds\x12\x16.ads111x.v1.Thresholds\x1a\x16.ads111x.v1.Thresholds\x12:\n" +
+	"\x06Stream\x12\x19.ads111x.v1.StreamRequest\x1a\x13.ads111x.v1.Reading0\x01B!Z\x1fgithub.com/dgnorton/ads111x/rpcb\x06proto3"
+
+var (
+	file_ads111x_proto_rawDescOnce sync.Once
+	file_ads111x_proto_rawDescData []byte
+)
+
+func file_ads111x_proto_rawDescGZIP() []byte {
+	file_ads111x_proto_rawDescOnce.Do(func() {
+		file_ads111x_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ads111x_proto_rawDesc), len(file_ads111x_proto_rawDesc)))
+	})
+	return file_ads111x_proto_rawDescData
+}
+
+var file_ads111x_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ads111x_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_ads111x_proto_goTypes = []any{
+	(Input)(0),                    // 0: ads111x.v1.Input
+	(*ReadRequest)(nil),           // 1: ads111x.v1.ReadRequest
+	(*Reading)(nil),               // 2: ads111x.v1.Reading
+	(*GetConfigRequest)(nil),      // 3: ads111x.v1.GetConfigRequest
+	(*Config)(nil),                // 4: ads111x.v1.Config
+	(*UpdateConfigRequest)(nil),   // 5: ads111x.v1.UpdateConfigRequest
+	(*GetThresholdsRequest)(nil),  // 6: ads111x.v1.GetThresholdsRequest
+	(*Thresholds)(nil),            // 7: ads111x.v1.Thresholds
+	(*StreamRequest)(nil),         // 8: ads111x.v1.StreamRequest
+	nil,                           // 9: ads111x.v1.Config.FieldsEntry
+	nil,                           // 10: ads111x.v1.UpdateConfigRequest.FieldsEntry
+	(*timestamppb.Timestamp)(nil), // 11: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 12: google.protobuf.Duration
+}
+var file_ads111x_proto_depIdxs = []int32{
+	0,  // 0: ads111x.v1.ReadRequest.input:type_name -> ads111x.v1.Input
+	0,  // 1: ads111x.v1.Reading.input:type_name -> ads111x.v1.Input
+	11, // 2: ads111x.v1.Reading.time:type_name -> google.protobuf.Timestamp
+	9,  // 3: ads111x.v1.Config.fields:type_name -> ads111x.v1.Config.FieldsEntry
+	10, // 4: ads111x.v1.UpdateConfigRequest.fields:type_name -> ads111x.v1.UpdateConfigRequest.FieldsEntry
+	0,  // 5: ads111x.v1.StreamRequest.inputs:type_name -> ads111x.v1.Input
+	12, // 6: ads111x.v1.StreamRequest.interval:type_name -> google.protobuf.Duration
+	1,  // 7: ads111x.v1.ADC.Read:input_type -> ads111x.v1.ReadRequest
+	3,  // 8: ads111x.v1.ADC.GetConfig:input_type -> ads111x.v1.GetConfigRequest
+	5,  // 9: ads111x.v1.ADC.UpdateConfig:input_type -> ads111x.v1.UpdateConfigRequest
+	6,  // 10: ads111x.v1.ADC.GetThresholds:input_type -> ads111x.v1.GetThresholdsRequest
+	7,  // 11: ads111x.v1.ADC.SetThresholds:input_type -> ads111x.v1.Thresholds
+	8,  // 12: ads111x.v1.ADC.Stream:input_type -> ads111x.v1.StreamRequest
+	2,  // 13: ads111x.v1.ADC.Read:output_type -> ads111x.v1.Reading
+	4,  // 14: ads111x.v1.ADC.GetConfig:output_type -> ads111x.v1.Config
+	4,  // 15: ads111x.v1.ADC.UpdateConfig:output_type -> ads111x.v1.Config
+	7,  // 16: ads111x.v1.ADC.GetThresholds:output_type -> ads111x.v1.Thresholds
+	7,  // 17: ads111x.v1.ADC.SetThresholds:output_type -> ads111x.v1.Thresholds
+	2,  // 18: ads111x.v1.ADC.Stream:output_type -> ads111x.v1.Reading
+	13, // [13:19] is the sub-list for method output_type
+	7,  // [7:13] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_ads111x_proto_init() }
+func file_ads111x_proto_init() {
+	if File_ads111x_proto != nil {
+		return
+	}
+	file_ads111x_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ads111x_proto_rawDesc), len(file_ads111x_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ads111x_proto_goTypes,
+		DependencyIndexes: file_ads111x_proto_depIdxs,
+		EnumInfos:         file_ads111x_proto_enumTypes,
+		MessageInfos:      file_ads111x_proto_msgTypes,
+	}.Build()
+	File_ads111x_proto = out.File
+	file_ads111x_proto_goTypes = nil
+	file_ads111x_proto_depIdxs = nil
+}
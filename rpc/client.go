@@ -0,0 +1,215 @@
+package rpc
+
+import (
+	"context"
+	"encoding"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+var _ ads111x.Device = (*Client)(nil)
+
+// Client is an ADC served by the ADC service. It implements ads111x.Device,
+// whose methods use a background context; Read, GetConfig, UpdateConfig and
+// Stream take one.
+type Client struct {
+	c ADCClient
+}
+
+// NewClient returns a client that calls the service on cc.
+func NewClient(cc grpc.ClientConnInterface) *Client {
+	return &Client{c: NewADCClient(cc)}
+}
+
+// Close does nothing. The connection the client was created with is
+// closed by its owner.
+func (c *Client) Close() error {
+	return nil
+}
+
+// Read reads an input.
+func (c *Client) Read(ctx context.Context, input ads111x.AIN) (*Reading, error) {
+	return c.c.Read(ctx, &ReadRequest{Input: InputOf(input)})
+}
+
+// ReadVolts reads the voltage from the input.
+func (c *Client) ReadVolts(input ads111x.AIN) (float64, error) {
+	r, err := c.Read(context.Background(), input)
+	if err != nil {
+		return 0, err
+	}
+	return r.Volts, nil
+}
+
+// ReadAIN reads the conversion value from the input.
+func (c *Client) ReadAIN(input ads111x.AIN) (uint16, error) {
+	r, err := c.Read(context.Background(), input)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(r.Raw), nil
+}
+
+// GetConfig returns the config register and its fields.
+func (c *Client) GetConfig(ctx context.Context) (*Config, error) {
+	return c.c.GetConfig(ctx, &GetConfigRequest{})
+}
+
+// UpdateConfig sets the config register or fields, and returns the new
+// config.
+func (c *Client) UpdateConfig(ctx context.Context, req *UpdateConfigRequest) (*Config, error) {
+	return c.c.UpdateConfig(ctx, req)
+}
+
+// Config returns the config register.
+func (c *Client) Config() (uint16, error) {
+	cfg, err := c.GetConfig(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return uint16(cfg.Raw), nil
+}
+
+// WriteConfig writes the config register.
+func (c *Client) WriteConfig(cfg uint16) error {
+	raw := uint32(cfg)
+	_, err := c.UpdateConfig(context.Background(), &UpdateConfigRequest{Raw: &raw})
+	return err
+}
+
+// field returns the config register masked to a field.
+func (c *Client) field(mask uint16) (uint16, error) {
+	cfg, err := c.Config()
+	return cfg & mask, err
+}
+
+// setField sets a field to v's text form.
+func (c *Client) setField(name string, v encoding.TextMarshaler) error {
+	b, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	_, err = c.UpdateConfig(context.Background(), &UpdateConfigRequest{Fields: map[string]string{name: string(b)}})
+	return err
+}
+
+// Status returns the current status.
+func (c *Client) Status() (ads111x.Status, error) {
+	v, err := c.field(ads111x.Status_Mask)
+	return ads111x.Status(v), err
+}
+
+// Mode returns the mode config setting.
+func (c *Client) Mode() (ads111x.Mode, error) {
+	v, err := c.field(ads111x.Mode_Mask)
+	return ads111x.Mode(v), err
+}
+
+// SetMode sets the mode of operation.
+func (c *Client) SetMode(m ads111x.Mode) error { return c.setField("mode", m) }
+
+// Scale returns the full scale range config setting.
+func (c *Client) Scale() (ads111x.Scale, error) {
+	v, err := c.field(ads111x.Scale_Mask)
+	return ads111x.Scale(v), err
+}
+
+// SetScale sets the full scale range.
+func (c *Client) SetScale(fs ads111x.Scale) error { return c.setField("scale", fs) }
+
+// DataRate returns the data rate config setting.
+func (c *Client) DataRate() (ads111x.DataRate, error) {
+	v, err := c.field(ads111x.DataRate_Mask)
+	return ads111x.DataRate(v), err
+}
+
+// SetDataRate sets the data rate.
+func (c *Client) SetDataRate(dr ads111x.DataRate) error { return c.setField("rate", dr) }
+
+// ComparatorMode returns the comparator mode config setting.
+func (c *Client) ComparatorMode() (ads111x.ComparatorMode, error) {
+	v, err := c.field(ads111x.ComparatorMode_Mask)
+	return ads111x.ComparatorMode(v), err
+}
+
+// SetComparatorMode sets the comparator mode.
+func (c *Client) SetComparatorMode(cm ads111x.ComparatorMode) error {
+	return c.setField("comp-mode", cm)
+}
+
+// ComparatorPolarity returns the comparator polarity config setting.
+func (c *Client) ComparatorPolarity() (ads111x.ComparatorPolarity, error) {
+	v, err := c.field(ads111x.ComparatorPolarity_Mask)
+	return ads111x.ComparatorPolarity(v), err
+}
+
+// SetComparatorPolarity sets the comparator polarity.
+func (c *Client) SetComparatorPolarity(cp ads111x.ComparatorPolarity) error {
+	return c.setField("comp-polarity", cp)
+}
+
+// ComparatorLatching returns the comparator latching config setting.
+func (c *Client) ComparatorLatching() (ads111x.ComparatorLatching, error) {
+	v, err := c.field(ads111x.ComparatorLatching_Mask)
+	return ads111x.ComparatorLatching(v), err
+}
+
+// SetComparatorLatching sets the comparator latching.
+func (c *Client) SetComparatorLatching(cl ads111x.ComparatorLatching) error {
+	return c.setField("comp-latch", cl)
+}
+
+// ComparatorQueue returns the comparator queue config setting.
+func (c *Client) ComparatorQueue() (ads111x.ComparatorQueue, error) {
+	v, err := c.field(ads111x.ComparatorQueue_Mask)
+	return ads111x.ComparatorQueue(v), err
+}
+
+// SetComparatorQueue sets the comparator queue.
+func (c *Client) SetComparatorQueue(cq ads111x.ComparatorQueue) error {
+	return c.setField("comp-queue", cq)
+}
+
+// Thresholds returns the comparator thresholds.
+func (c *Client) Thresholds() (lo, hi int16, err error) {
+	t, err := c.c.GetThresholds(context.Background(), &GetThresholdsRequest{})
+	if err != nil {
+		return 0, 0, err
+	}
+	return int16(t.Lo), int16(t.Hi), nil
+}
+
+// SetThresholds sets the comparator thresholds.
+func (c *Client) SetThresholds(lo, hi int16) error {
+	_, err := c.c.SetThresholds(context.Background(), &Thresholds{Lo: int32(lo), Hi: int32(hi)})
+	return err
+}
+
+// Stream is a stream of readings.
+type Stream struct {
+	sc ADC_StreamClient
+}
+
+// Stream reads the inputs every interval until ctx is done. If n isn't 0,
+// the stream ends after n rounds of readings.
+func (c *Client) Stream(ctx context.Context, interval time.Duration, n int, inputs ...ads111x.AIN) (*Stream, error) {
+	req := &StreamRequest{Interval: durationpb.New(interval), Count: uint32(n)}
+	for _, in := range inputs {
+		req.Inputs = append(req.Inputs, InputOf(in))
+	}
+	sc, err := c.c.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{sc: sc}, nil
+}
+
+// Next returns the next reading. It returns io.EOF at the end of the
+// stream. The server stops sampling when a client that isn't calling Next
+// has as many readings buffered as flow control allows.
+func (s *Stream) Next() (*Reading, error) {
+	return s.sc.Recv()
+}
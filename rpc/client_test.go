@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readScaled is application code written against the interface.
+func readScaled(d ads111x.Device, fs ads111x.Scale, input ads111x.AIN) (float64, error) {
+	if err := d.SetScale(fs); err != nil {
+		return 0, err
+	}
+	return d.ReadVolts(input)
+}
+
+func Test_Client(t *testing.T) {
+	dev, c := newTestClient(t)
+	dev.SetReg(ads111x.ConversionReg, 0x4000)
+
+	v, err := readScaled(c, ads111x.Scale_1_024V, ads111x.AIN_2_3)
+	if err != nil {
+		t.Fatal(err)
+	} else if v != 0.512 {
+		t.Fatalf("exp = 0.512, got = %v", v)
+	}
+	if raw, err := c.ReadAIN(ads111x.AIN_2_3); err != nil || raw != 0x4000 {
+		t.Fatalf("exp = 0x4000, got = 0x%x, %v", raw, err)
+	}
+
+	if err := c.SetDataRate(ads111x.DR_16SPS); err != nil {
+		t.Fatal(err)
+	} else if dr, err := c.DataRate(); err != nil || dr != ads111x.DR_16SPS {
+		t.Fatalf("exp = %v, got = %v, %v", ads111x.DR_16SPS, dr, err)
+	}
+	if err := c.SetComparatorQueue(ads111x.AfterTwo); err != nil {
+		t.Fatal(err)
+	} else if cq, err := c.ComparatorQueue(); err != nil || cq != ads111x.AfterTwo {
+		t.Fatalf("exp = %v, got = %v, %v", ads111x.AfterTwo, cq, err)
+	}
+	if err := c.WriteConfig(0); err != nil {
+		t.Fatal(err)
+	} else if cfg, err := c.Config(); err != nil || cfg != 0 {
+		t.Fatalf("exp = 0, got = 0x%x, %v", cfg, err)
+	}
+	if err := c.SetThresholds(-5, 5); err != nil {
+		t.Fatal(err)
+	} else if lo, hi, err := c.Thresholds(); err != nil || lo != -5 || hi != 5 {
+		t.Fatalf("exp = -5, 5, got = %d, %d, %v", lo, hi, err)
+	}
+
+	// Invalid values fail before they're sent.
+	if err := c.SetScale(ads111x.Scale(7 << ads111x.Scale_LSB)); err == nil {
+		t.Fatal("expected error")
+	}
+	dev.SetFail(errors.New("remote I/O error"))
+	if _, err := c.ReadVolts(ads111x.AIN_0_GND); status.Code(err) != codes.Internal {
+		t.Fatalf("exp = %v, got = %v", codes.Internal, err)
+	}
+}
+
+func Test_ClientStream(t *testing.T) {
+	dev, c := newTestClient(t)
+	dev.SetReg(ads111x.ConversionReg, 0x2000)
+
+	s, err := c.Stream(context.Background(), time.Millisecond, 2, ads111x.AIN_0_GND, ads111x.AIN_3_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []ads111x.AIN{ads111x.AIN_0_GND, ads111x.AIN_3_GND, ads111x.AIN_0_GND, ads111x.AIN_3_GND}
+	for _, in := range exp {
+		r, err := s.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if r.Input != InputOf(in) || r.Volts != 0.512 {
+			t.Fatalf("unexpected reading: %+v", r)
+		}
+	}
+	if _, err := s.Next(); err != io.EOF {
+		t.Fatalf("exp = EOF, got = %v", err)
+	}
+
+	// An unbounded stream ends when its context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	s, err = c.Stream(ctx, time.Millisecond, 0, ads111x.AIN_0_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Next(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for err == nil {
+		_, err = s.Next()
+	}
+	if status.Code(err) != codes.Canceled {
+		t.Fatalf("exp = %v, got = %v", codes.Canceled, err)
+	}
+
+	// Invalid requests fail on the first Next.
+	s, err = c.Stream(context.Background(), time.Microsecond, 0, ads111x.AIN_0_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Next(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("exp = %v, got = %v", codes.InvalidArgument, err)
+	}
+}
@@ -0,0 +1,20 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// InputOf returns the Input for an AIN.
+func InputOf(a ads111x.AIN) Input {
+	return Input(a>>ads111x.AIN_LSB) + 1
+}
+
+// AIN returns the input's AIN.
+func (in Input) AIN() (ads111x.AIN, error) {
+	if in < Input_INPUT_AIN0_AIN1 || in > Input_INPUT_AIN3_GND {
+		return 0, fmt.Errorf("invalid input %d", int32(in))
+	}
+	return ads111x.AIN(in-1) << ads111x.AIN_LSB, nil
+}
@@ -0,0 +1,23 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+)
+
+func Test_Input(t *testing.T) {
+	for _, a := range []ads111x.AIN{ads111x.AIN_0_1, ads111x.AIN_2_3, ads111x.AIN_3_GND} {
+		if got, err := InputOf(a).AIN(); err != nil || got != a {
+			t.Fatalf("exp = %v, got = %v, %v", a, got, err)
+		}
+	}
+	if InputOf(ads111x.AIN_0_1) != Input_INPUT_AIN0_AIN1 || InputOf(ads111x.AIN_3_GND) != Input_INPUT_AIN3_GND {
+		t.Fatal("unexpected input values")
+	}
+	for _, in := range []Input{Input_INPUT_UNSPECIFIED, 9} {
+		if _, err := in.AIN(); err == nil {
+			t.Fatalf("expected error for %v", in)
+		}
+	}
+}
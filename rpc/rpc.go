@@ -0,0 +1,11 @@
+// Package rpc serves an ADC with gRPC, as the ADC service in
+// ads111x.proto, and provides a client that implements ads111x.Device.
+//
+// The messages and service are generated from ads111x.proto, and a Server
+// is registered like any generated service:
+//
+//	s := grpc.NewServer()
+//	rpc.RegisterADCServer(s, rpc.NewServer(adc))
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative ads111x.proto
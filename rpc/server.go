@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MinStreamInterval is the shortest interval a stream can ask for.
+const MinStreamInterval = time.Millisecond
+
+// now is for test purposes.
+var now = time.Now
+
+// Server implements the ADC service for an ADC. Calls are serialized, so
+// the ADC is only used by one at a time.
+type Server struct {
+	UnimplementedADCServer
+	mu  sync.Mutex
+	adc ads111x.Device
+}
+
+// NewServer returns a server for the ADC.
+func NewServer(adc ads111x.Device) *Server {
+	return &Server{adc: adc}
+}
+
+// Read reads an input with the ADC's current scale.
+func (s *Server) Read(ctx context.Context, req *ReadRequest) (*Reading, error) {
+	input, err := req.Input.AIN()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.read(input)
+}
+
+func (s *Server) read(input ads111x.AIN) (*Reading, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fs, err := s.adc.Scale()
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	cnt, err := s.adc.ReadAIN(input)
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	return &Reading{Input: InputOf(input), Raw: int32(int16(cnt)), Volts: ads111x.Volts(cnt, fs), Time: timestamppb.New(now())}, nil
+}
+
+// GetConfig returns the config register and its fields.
+func (s *Server) GetConfig(ctx context.Context, req *GetConfigRequest) (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, err := s.adc.Config()
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	return newConfig(cfg), nil
+}
+
+// UpdateConfig sets the config register, if Raw is set, and then the
+// fields, and returns the new config.
+func (s *Server) UpdateConfig(ctx context.Context, req *UpdateConfigRequest) (*Config, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg, err := s.adc.Config()
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	if req.Raw != nil {
+		if *req.Raw > 0xffff {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid config 0x%x", *req.Raw)
+		}
+		cfg = uint16(*req.Raw)
+	}
+	for name, value := range req.Fields {
+		if cfg, err = ads111x.SetFieldText(cfg, name, value); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := s.adc.WriteConfig(cfg); err != nil {
+		return nil, deviceError(err)
+	}
+	if cfg, err = s.adc.Config(); err != nil {
+		return nil, deviceError(err)
+	}
+	return newConfig(cfg), nil
+}
+
+func newConfig(cfg uint16) *Config {
+	return &Config{Raw: uint32(cfg), Fields: ads111x.ConfigText(cfg)}
+}
+
+// GetThresholds returns the comparator thresholds.
+func (s *Server) GetThresholds(ctx context.Context, req *GetThresholdsRequest) (*Thresholds, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lo, hi, err := s.adc.Thresholds()
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	return &Thresholds{Lo: int32(lo), Hi: int32(hi)}, nil
+}
+
+// SetThresholds sets the comparator thresholds and returns them.
+func (s *Server) SetThresholds(ctx context.Context, req *Thresholds) (*Thresholds, error) {
+	if int32(int16(req.Lo)) != req.Lo || int32(int16(req.Hi)) != req.Hi {
+		return nil, status.Errorf(codes.InvalidArgument, "thresholds %d, %d out of range", req.Lo, req.Hi)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.adc.SetThresholds(int16(req.Lo), int16(req.Hi)); err != nil {
+		return nil, deviceError(err)
+	}
+	lo, hi, err := s.adc.Thresholds()
+	if err != nil {
+		return nil, deviceError(err)
+	}
+	return &Thresholds{Lo: int32(lo), Hi: int32(hi)}, nil
+}
+
+// Stream reads the inputs every interval and sends the readings. The inputs
+// are read as they're sent, and Send blocks while the client's flow control
+// window is full, so a slow client slows the sampling: ticks missed while
+// blocked are dropped instead of queued.
+func (s *Server) Stream(req *StreamRequest, stream ADC_StreamServer) error {
+	if len(req.Inputs) == 0 {
+		return status.Error(codes.InvalidArgument, "no inputs")
+	}
+	inputs := make([]ads111x.AIN, len(req.Inputs))
+	for i, in := range req.Inputs {
+		var err error
+		if inputs[i], err = in.AIN(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	interval := req.Interval.AsDuration()
+	if interval < MinStreamInterval {
+		return status.Errorf(codes.InvalidArgument, "invalid interval %v, must be at least %v", interval, MinStreamInterval)
+	}
+
+	ctx := stream.Context()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for i := uint32(0); req.Count == 0 || i < req.Count; i++ {
+		if i > 0 {
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		for _, input := range inputs {
+			r, err := s.read(input)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deviceError returns the status for an error from the ADC.
+func deviceError(err error) error {
+	return status.Error(codes.Internal, err.Error())
+}
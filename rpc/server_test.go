@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_Server(t *testing.T) {
+	dev, c := newTestClient(t)
+	dev.SetReg(ads111x.ConversionReg, 0x4000)
+	ctx := context.Background()
+
+	r, err := c.Read(ctx, ads111x.AIN_1_GND)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := &Reading{Input: InputOf(ads111x.AIN_1_GND), Raw: 16384, Volts: 1.024, Time: timestamppb.New(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))}
+	if !proto.Equal(r, exp) {
+		t.Fatalf("exp = %v, got = %v", exp, r)
+	}
+
+	cfg, err := c.GetConfig(ctx)
+	if err != nil {
+		t.Fatal(err)
+	} else if cfg.Fields["input"] != "ain1-gnd" || cfg.Fields["scale"] != "2.048V" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	cfg, err = c.UpdateConfig(ctx, &UpdateConfigRequest{Fields: map[string]string{"scale": "4.096V", "rate": "860"}})
+	if err != nil {
+		t.Fatal(err)
+	} else if cfg.Fields["scale"] != "4.096V" || cfg.Fields["rate"] != "860sps" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+	if r, err := c.Read(ctx, ads111x.AIN_1_GND); err != nil || r.Volts != 2.048 {
+		t.Fatalf("exp = 2.048, got = %+v, %v", r, err)
+	}
+	// Writing the status bit would start a conversion. It reads back as
+	// idle.
+	raw := uint32(ads111x.DefaultConfig &^ ads111x.Status_Mask)
+	if cfg, err = c.UpdateConfig(ctx, &UpdateConfigRequest{Raw: &raw}); err != nil || cfg.Raw != uint32(ads111x.DefaultConfig) {
+		t.Fatalf("unexpected config: %+v, %v", cfg, err)
+	}
+
+	test := func(code codes.Code, exp string, err error) {
+		t.Helper()
+		if s := status.Convert(err); s.Code() != code || s.Message() != exp {
+			t.Fatalf("exp = %v: %s, got = %v", code, exp, err)
+		}
+	}
+	_, err = c.Read(ctx, ads111x.AIN(9<<ads111x.AIN_LSB))
+	test(codes.InvalidArgument, "invalid input 10", err)
+	_, err = c.UpdateConfig(ctx, &UpdateConfigRequest{Fields: map[string]string{"status": "busy"}})
+	test(codes.InvalidArgument, "status is read-only", err)
+	raw = 0x10000
+	_, err = c.UpdateConfig(ctx, &UpdateConfigRequest{Raw: &raw})
+	test(codes.InvalidArgument, "invalid config 0x10000", err)
+	_, err = c.c.SetThresholds(ctx, &Thresholds{Lo: -40000})
+	test(codes.InvalidArgument, "thresholds -40000, 0 out of range", err)
+
+	dev.SetFail(errors.New("remote I/O error"))
+	_, err = c.Read(ctx, ads111x.AIN_0_GND)
+	test(codes.Internal, "remote I/O error", err)
+}
+
+func Test_ServerStream(t *testing.T) {
+	dev, srv := newTestDevice(t)
+	dev.SetReg(ads111x.ConversionReg, 0x2000)
+
+	test := func(code codes.Code, req *StreamRequest) {
+		t.Helper()
+		if err := srv.Stream(req, &blockingStream{ctx: context.Background()}); status.Code(err) != code {
+			t.Fatalf("exp = %v, got = %v", code, err)
+		}
+	}
+	test(codes.InvalidArgument, &StreamRequest{Interval: durationpb.New(time.Millisecond)})
+	test(codes.InvalidArgument, &StreamRequest{Inputs: []Input{0}, Interval: durationpb.New(time.Millisecond)})
+	test(codes.InvalidArgument, &StreamRequest{Inputs: []Input{1}, Interval: durationpb.New(time.Microsecond)})
+
+	// Nothing is read while Send is blocked, and the ticks missed while it
+	// was blocked are dropped instead of read in a burst.
+	s := &blockingStream{ctx: context.Background(), unblock: make(chan struct{}), sent: make(chan *Reading)}
+	done := make(chan error)
+	go func() {
+		done <- srv.Stream(&StreamRequest{Inputs: []Input{1}, Interval: durationpb.New(time.Millisecond), Count: 3}, s)
+	}()
+	for i := 1; i <= 3; i++ {
+		<-s.sent
+		time.Sleep(20 * time.Millisecond)
+		if n := dev.Reads(); n != i {
+			t.Fatalf("exp = %d reads, got = %d", i, n)
+		}
+		s.unblock <- struct{}{}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// Each reading is a conversion of its own input.
+	dev.SetInput(uint16(ads111x.AIN_0_GND), 0x1000)
+	dev.SetInput(uint16(ads111x.AIN_1_GND), 0x2000)
+	s = &blockingStream{ctx: context.Background(), sent: make(chan *Reading, 4)}
+	in0, in1 := InputOf(ads111x.AIN_0_GND), InputOf(ads111x.AIN_1_GND)
+	if err := srv.Stream(&StreamRequest{Inputs: []Input{in0, in1}, Interval: durationpb.New(time.Millisecond), Count: 2}, s); err != nil {
+		t.Fatal(err)
+	}
+	close(s.sent)
+	var raws []int32
+	for r := range s.sent {
+		raws = append(raws, r.Raw)
+	}
+	if exp := []int32{0x1000, 0x2000, 0x1000, 0x2000}; !reflect.DeepEqual(raws, exp) {
+		t.Fatalf("exp = %v, got = %v", exp, raws)
+	}
+}
+
+// blockingStream is an ADC_StreamServer whose Send blocks until unblock is
+// received from, if it's set.
+type blockingStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	sent    chan *Reading
+	unblock chan struct{}
+}
+
+func (s *blockingStream) Send(r *Reading) error {
+	if s.sent != nil {
+		s.sent <- r
+	}
+	if s.unblock != nil {
+		<-s.unblock
+	}
+	return nil
+}
+
+func (s *blockingStream) Context() context.Context { return s.ctx }
+
+// newTestDevice returns a server for an ADC on a fake device.
+func newTestDevice(t *testing.T) (*fakedev.Device, *Server) {
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() { now = time.Now })
+	dev := fakedev.New()
+	adc, err := ads111x.NewBus(dev).OpenADC(ads111x.Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dev, NewServer(adc)
+}
+
+// newTestClient returns a client connected to a server for an ADC on a
+// fake device.
+func newTestClient(t *testing.T) (*fakedev.Device, *Client) {
+	dev, srv := newTestDevice(t)
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterADCServer(s, srv)
+	go s.Serve(lis)
+	// GracefulStop waits for handlers, which use now, to return.
+	t.Cleanup(s.GracefulStop)
+
+	cc, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return dev, NewClient(cc)
+}
@@ -0,0 +1,204 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Samples is a window of conversions captured from one input.
+type Samples struct {
+	// Input is the input that was sampled.
+	Input AIN
+	// Scale is the full scale range the samples were captured with.
+	Scale Scale
+	// DataRate is the data rate the samples were captured with.
+	DataRate DataRate
+	// Start is when the first conversion finished.
+	Start time.Time
+	// Volts are the sample values.
+	Volts []float64
+	// Offsets are when each conversion finished, relative to Start. It may
+	// be empty, in which case samples are assumed to be evenly spaced at
+	// the nominal data rate.
+	Offsets []time.Duration
+}
+
+// Rate returns the nominal samples per second.
+func (s *Samples) Rate() float64 {
+	return SamplesPerSecond(s.DataRate)
+}
+
+// Duration returns the time between the first and last samples.
+func (s *Samples) Duration() time.Duration {
+	if len(s.Offsets) > 0 {
+		return s.Offsets[len(s.Offsets)-1]
+	}
+	if len(s.Volts) < 2 {
+		return 0
+	}
+	return time.Duration(float64(len(s.Volts)-1) / s.Rate() * float64(time.Second))
+}
+
+// MeasuredRate returns the sample rate measured from the sample offsets, or
+// the nominal rate if there are no offsets.
+func (s *Samples) MeasuredRate() float64 {
+	d := s.Duration()
+	if len(s.Offsets) < 2 || d <= 0 {
+		return s.Rate()
+	}
+	return float64(len(s.Offsets)-1) / d.Seconds()
+}
+
+// timed returns true if the samples have per-sample offsets.
+func (s *Samples) timed() bool {
+	return len(s.Offsets) == len(s.Volts) && len(s.Volts) > 0
+}
+
+// at returns the time of sample i in seconds since Start.
+func (s *Samples) at(i int) float64 {
+	if s.timed() {
+		return s.Offsets[i].Seconds()
+	}
+	return float64(i) / s.Rate()
+}
+
+// Sample captures n successive conversions of input. If the ADC has an
+// alert line and conversion ready is enabled (see EnableConversionReady),
+// the ADC is put in continuous mode and each conversion is read when the
+// ALERT/RDY pin pulses, so the samples are paced by the device's clock at
+// the data rate. Otherwise each sample is a single-shot conversion started
+// when the previous one has been read, so they come slower than the data
+// rate by the time spent on the bus in between. Either way the offsets are
+// when the conversions finished, so MeasuredRate is the actual rate. The
+// config is restored when Sample returns.
+func (adc *ADC) Sample(input AIN, n int) (_ *Samples, err error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid sample count %d", n)
+	}
+	sp, err := adc.newSampler(input)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := sp.close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	s := &Samples{
+		Input:    input,
+		Scale:    sp.scale,
+		DataRate: sp.rate,
+		Volts:    make([]float64, 0, n),
+		Offsets:  make([]time.Duration, 0, n),
+	}
+	for i := 0; i < n; i++ {
+		t, v, err := sp.next(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			s.Start = t
+		}
+		s.Volts = append(s.Volts, v)
+		s.Offsets = append(s.Offsets, t.Sub(s.Start))
+	}
+	return s, nil
+}
+
+// sampler reads successive conversions from one input.
+type sampler struct {
+	adc   *ADC
+	scale Scale
+	rate  DataRate
+	// ready is set when conversions are paced by the ALERT/RDY pin in
+	// continuous mode. Otherwise cfg starts a single-shot conversion.
+	ready bool
+	cfg   uint16
+	// orig is the config to restore in ready mode.
+	orig uint16
+	// skip is set while the next pulse may be for a conversion made with
+	// the previous config.
+	skip bool
+}
+
+// newSampler selects input and, if conversion ready is enabled, puts the
+// ADC in continuous mode until the sampler is closed.
+func (adc *ADC) newSampler(input AIN) (*sampler, error) {
+	cfg, err := adc.Config()
+	if err != nil {
+		return nil, err
+	}
+	sp := &sampler{
+		adc:   adc,
+		scale: Scale(cfg & Scale_Mask),
+		rate:  DataRate(cfg & DataRate_Mask),
+	}
+	if adc.alert != nil {
+		if sp.ready, err = adc.conversionReady(); err != nil {
+			return nil, err
+		}
+	}
+	if !sp.ready {
+		sp.cfg = cfg&^(AIN_Mask|Mode_Mask) | uint16(input) | uint16(Single)
+		return sp, nil
+	}
+	// Writing the status bit back would start a conversion.
+	sp.orig = cfg &^ Status_Mask
+	cfg = cfg&^(AIN_Mask|Mode_Mask) | uint16(input) | uint16(Continuous)
+	if err := adc.WriteConfig(cfg); err != nil {
+		return nil, err
+	}
+	sp.skip = true
+	return sp, nil
+}
+
+// close restores the config newSampler changed in ready mode.
+func (sp *sampler) close() error {
+	if !sp.ready {
+		return nil
+	}
+	return sp.adc.WriteConfig(sp.orig)
+}
+
+// next waits for the next conversion and returns when it finished and its
+// value in volts.
+func (sp *sampler) next(ctx context.Context) (time.Time, float64, error) {
+	var t time.Time
+	var err error
+	if sp.ready {
+		t, err = sp.waitReady(ctx)
+	} else {
+		t, err = sp.adc.singleShot(sp.cfg)
+	}
+	if err != nil {
+		return t, 0, err
+	}
+	cnt, err := sp.adc.ReadRegUint16(ConversionReg)
+	if err != nil {
+		return t, 0, err
+	}
+	return t, Volts(cnt, sp.scale), nil
+}
+
+// waitReady waits for the ALERT/RDY pin to pulse for a conversion and
+// returns when it did.
+func (sp *sampler) waitReady(ctx context.Context) (time.Time, error) {
+	for {
+		wctx, cancel := context.WithTimeout(ctx, 2*conversionTimeout(sp.rate))
+		t, err := sp.adc.alert.WaitEdge(wctx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && err == context.DeadlineExceeded {
+				err = errors.New("timed out waiting for conversion ready")
+			}
+			return t, err
+		}
+		if !sp.skip {
+			return t, nil
+		}
+		sp.skip = false
+	}
+}
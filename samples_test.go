@@ -0,0 +1,104 @@
+package ads111x
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Sample(t *testing.T) {
+	clock := useFakeClock()
+	defer clock.restore()
+
+	// Each conversion reads 0x100 counts more than the last.
+	var n uint16
+	dev := fakedev.New()
+	dev.SetConvert(func(cfg uint16, _ time.Time) uint16 {
+		if AIN(cfg&AIN_Mask) != AIN_2_GND {
+			return 0
+		}
+		n++
+		return n * 0x100
+	})
+	adc := newFakeADC(dev)
+	defer mustClose(adc)
+
+	// Without conversion ready, each sample is a single-shot conversion.
+	s, err := adc.Sample(AIN_2_GND, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range s.Volts {
+		if exp := Volts(uint16(i+1)*0x100, Scale_2_048V); v != exp {
+			t.Fatalf("sample %d: exp = %f, got = %f", i, exp, v)
+		}
+	}
+	if mode := Mode(dev.Reg(ConfigReg) & Mode_Mask); mode != Single {
+		t.Fatalf("exp = %v, got = %v", Single, mode)
+	}
+	if s.DataRate != DR_128SPS || s.Input != AIN_2_GND {
+		t.Fatalf("unexpected data rate or input: %+v", s)
+	}
+	// Status polling finds each conversion finished within a poll.
+	ct := ConversionTime(DR_128SPS)
+	if got := s.Duration(); got < 7*ct || got > 7*(ct+convPoll) {
+		t.Fatalf("exp = %v to %v, got = %v", 7*ct, 7*(ct+convPoll), got)
+	}
+
+	// With conversion ready, the device paces the samples in continuous
+	// mode, here 10% slower than nominal.
+	adc.SetAlertLine(&fakeLine{delay: time.Duration(float64(ct) / 0.9)})
+	if err := adc.EnableConversionReady(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := adc.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err = adc.Sample(AIN_2_GND, 8); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.MeasuredRate(); got < 115.1 || got > 115.3 {
+		t.Fatalf("exp = 115.2, got = %f", got)
+	}
+	// The config is restored afterwards, without starting a conversion.
+	if got := dev.Reg(ConfigReg); got != cfg&^Status_Mask {
+		t.Fatalf("exp = 0x%04x, got = 0x%04x", cfg&^Status_Mask, got)
+	}
+
+	// So it is after a capture.
+	c, err := adc.NewCapture(AIN_2_GND, Trigger{Type: TriggerWindow, Low: -1, High: 0.1}, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Once(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := dev.Reg(ConfigReg); got != cfg&^Status_Mask {
+		t.Fatalf("exp = 0x%04x, got = 0x%04x", cfg&^Status_Mask, got)
+	}
+
+	if _, err := adc.Sample(AIN_2_GND, 0); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// fakeClock replaces now and sleep with a clock that only advances when
+// sleep is called.
+type fakeClock struct {
+	t time.Time
+}
+
+func useFakeClock() *fakeClock {
+	c := &fakeClock{t: time.Unix(1000, 0)}
+	now = func() time.Time { return c.t }
+	sleep = func(d time.Duration) { c.t = c.t.Add(d) }
+	return c
+}
+
+func (c *fakeClock) restore() {
+	now = time.Now
+	sleep = time.Sleep
+}
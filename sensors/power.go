@@ -0,0 +1,224 @@
+package sensors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// now is for test purposes.
+var now = time.Now
+
+// DefaultMaxGap is the longest interval between samples a PowerMeter
+// integrates over.
+const DefaultMaxGap = 10 * time.Second
+
+// PowerReading is a single power measurement.
+type PowerReading struct {
+	Time  time.Time
+	Volts float64
+	Amps  float64
+	Watts float64
+}
+
+// PowerTotals are the running totals of a PowerMeter.
+type PowerTotals struct {
+	// EnergyWh is the integrated energy in watt hours.
+	EnergyWh float64 `json:"energy_wh"`
+	// ChargeAh is the integrated charge in amp hours.
+	ChargeAh float64 `json:"charge_ah"`
+	// Covered is the total time integrated.
+	Covered time.Duration `json:"covered_ns"`
+	// Gaps is the number of intervals skipped because they exceeded the
+	// max gap, including restarts.
+	Gaps int `json:"gaps"`
+	// Missed is the total time in skipped intervals.
+	Missed time.Duration `json:"missed_ns"`
+	// Since is when the totals were last reset.
+	Since time.Time `json:"since"`
+	// Updated is the time of the last sample included in the totals.
+	Updated time.Time `json:"updated"`
+}
+
+// PowerMeter measures power from a voltage sensor and a current sensor,
+// e.g., a bus voltage through a Divider and a current through a Shunt, and
+// integrates energy over time.
+type PowerMeter struct {
+	mu        sync.Mutex
+	voltage   *Sensor
+	current   *Sensor
+	maxGap    time.Duration
+	stateFile string
+	saveEvery time.Duration
+	savedAt   time.Time
+	last      *PowerReading
+	totals    PowerTotals
+}
+
+// NewPowerMeter returns a power meter. If stateFile isn't empty, totals are
+// loaded from it if it exists and saved to it periodically by Sample.
+func NewPowerMeter(voltage, current *Sensor, stateFile string) (*PowerMeter, error) {
+	if u := voltage.Unit(); u != "V" {
+		return nil, fmt.Errorf("voltage sensor reports %q, not V", u)
+	}
+	if u := current.Unit(); u != "A" {
+		return nil, fmt.Errorf("current sensor reports %q, not A", u)
+	}
+	m := &PowerMeter{
+		voltage:   voltage,
+		current:   current,
+		maxGap:    DefaultMaxGap,
+		stateFile: stateFile,
+		saveEvery: time.Minute,
+		totals:    PowerTotals{Since: now()},
+	}
+	if stateFile != "" {
+		if err := m.load(); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// SetMaxGap sets the longest interval between samples that is integrated.
+// Longer intervals are counted as gaps and contribute no energy.
+func (m *PowerMeter) SetMaxGap(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxGap = d
+}
+
+// SetSaveInterval sets how often Sample saves totals to the state file.
+// Zero saves after every sample.
+func (m *PowerMeter) SetSaveInterval(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saveEvery = d
+}
+
+// Sample reads both sensors, computes power and adds the energy since the
+// previous sample to the totals using the trapezoidal rule.
+func (m *PowerMeter) Sample() (PowerReading, error) {
+	v, err := m.voltage.Read()
+	if err != nil {
+		return PowerReading{}, err
+	}
+	i, err := m.current.Read()
+	if err != nil {
+		return PowerReading{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := PowerReading{
+		Time:  now(),
+		Volts: v.Value,
+		Amps:  i.Value,
+		Watts: v.Value * i.Value,
+	}
+	if m.last == nil {
+		// The interval since the last saved sample, e.g., before a restart,
+		// wasn't observed.
+		if !m.totals.Updated.IsZero() && r.Time.After(m.totals.Updated) {
+			m.totals.Gaps++
+			m.totals.Missed += r.Time.Sub(m.totals.Updated)
+		}
+	} else if dt := r.Time.Sub(m.last.Time); dt > m.maxGap {
+		m.totals.Gaps++
+		m.totals.Missed += dt
+	} else if dt > 0 {
+		h := dt.Hours()
+		m.totals.EnergyWh += (m.last.Watts + r.Watts) / 2 * h
+		m.totals.ChargeAh += (m.last.Amps + r.Amps) / 2 * h
+		m.totals.Covered += dt
+	}
+	m.last = &r
+	m.totals.Updated = r.Time
+
+	if m.stateFile != "" && r.Time.Sub(m.savedAt) >= m.saveEvery {
+		if err := m.save(); err != nil {
+			return r, err
+		}
+	}
+	return r, nil
+}
+
+// Totals returns the running totals.
+func (m *PowerMeter) Totals() PowerTotals {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.totals
+}
+
+// Reset zeroes the totals.
+func (m *PowerMeter) Reset() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totals = PowerTotals{Since: now()}
+	m.last = nil
+	if m.stateFile == "" {
+		return nil
+	}
+	return m.save()
+}
+
+// Save writes the totals to the state file.
+func (m *PowerMeter) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stateFile == "" {
+		return nil
+	}
+	return m.save()
+}
+
+func (m *PowerMeter) load() error {
+	b, err := os.ReadFile(m.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var t PowerTotals
+	if err := json.Unmarshal(b, &t); err != nil {
+		return fmt.Errorf("%s: %w", m.stateFile, err)
+	}
+	m.totals = t
+	return nil
+}
+
+// save writes the state file atomically so a crash can't leave it truncated.
+func (m *PowerMeter) save() error {
+	b, err := json.Marshal(m.totals)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(m.stateFile), filepath.Base(m.stateFile)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), m.stateFile); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	m.savedAt = m.totals.Updated
+	return nil
+}
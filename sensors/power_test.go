@@ -0,0 +1,106 @@
+package sensors
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgnorton/ads111x"
+)
+
+func Test_PowerMeter(t *testing.T) {
+	defer func() { now = time.Now }()
+	t0 := time.Unix(1000, 0)
+	now = func() time.Time { return t0 }
+
+	r := &mockReader{volts: map[ads111x.AIN]float64{
+		ads111x.AIN_0_GND: 1.2,  // 12 V through a 9:1 divider
+		ads111x.AIN_2_3:   0.02, // 2 A through a 10 mΩ shunt
+	}}
+	voltage := New(r, ads111x.AIN_0_GND, Divider{Top: 90000, Bottom: 10000})
+	current := New(r, ads111x.AIN_2_3, Shunt{Ohms: 0.01})
+	state := filepath.Join(t.TempDir(), "power.json")
+
+	m, err := NewPowerMeter(voltage, current, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pr, err := m.Sample(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(pr.Watts-24) > 1e-9 {
+		t.Fatalf("exp = 24 W, got = %f W", pr.Watts)
+	}
+	// One hour at 24 W in 1 s steps.
+	for i := 0; i < 3600; i++ {
+		t0 = t0.Add(time.Second)
+		if _, err := m.Sample(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A gap longer than the max gap isn't integrated.
+	t0 = t0.Add(time.Minute)
+	if _, err := m.Sample(); err != nil {
+		t.Fatal(err)
+	}
+
+	tot := m.Totals()
+	if math.Abs(tot.EnergyWh-24) > 1e-6 || math.Abs(tot.ChargeAh-2) > 1e-6 {
+		t.Fatalf("exp = 24 Wh and 2 Ah, got = %f Wh and %f Ah", tot.EnergyWh, tot.ChargeAh)
+	} else if tot.Gaps != 1 || tot.Missed != time.Minute || tot.Covered != time.Hour {
+		t.Fatalf("unexpected totals: %+v", tot)
+	}
+
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Totals survive a restart and the restart is counted as a gap.
+	m, err = NewPowerMeter(voltage, current, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 = t0.Add(time.Second)
+	if _, err := m.Sample(); err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Totals(); math.Abs(got.EnergyWh-24) > 1e-6 || got.Gaps != 2 {
+		t.Fatalf("unexpected totals after restart: %+v", got)
+	}
+
+	if _, err := NewPowerMeter(current, voltage, ""); err == nil {
+		t.Fatal("expected error for swapped sensors")
+	}
+}
+
+func Test_PowerMeterADC(t *testing.T) {
+	defer func() { now = time.Now }()
+	t0 := time.Unix(1000, 0)
+	now = func() time.Time { return t0 }
+
+	// The voltage and current on one ADC, each read with its own scale.
+	adc := newFakeADC(t, map[ads111x.AIN]float64{
+		ads111x.AIN_0_GND: 1.2,
+		ads111x.AIN_2_3:   0.02,
+	})
+	r := scaledReader{adc: adc, scales: map[ads111x.AIN]ads111x.Scale{
+		ads111x.AIN_0_GND: ads111x.Scale_2_048V,
+		ads111x.AIN_2_3:   ads111x.Scale_0_256V,
+	}}
+	m, err := NewPowerMeter(New(r, ads111x.AIN_0_GND, Divider{Top: 90000, Bottom: 10000}), New(r, ads111x.AIN_2_3, Shunt{Ohms: 0.01}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		pr, err := m.Sample()
+		if err != nil {
+			t.Fatal(err)
+		} else if !near(pr.Volts, 12) || !near(pr.Amps, 2) {
+			t.Fatalf("exp = 12 V and 2 A, got = %f V and %f A", pr.Volts, pr.Amps)
+		}
+		t0 = t0.Add(time.Second)
+	}
+	if tot := m.Totals(); !near(tot.EnergyWh*3600, 24) {
+		t.Fatalf("exp = 24 Ws, got = %f Ws", tot.EnergyWh*3600)
+	}
+}
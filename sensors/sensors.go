@@ -0,0 +1,338 @@
+// Package sensors converts voltages read from an ADS111x into engineering
+// units using composable transfer functions.
+package sensors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dgnorton/ads111x"
+)
+
+// ErrOutOfRange is returned when a value falls outside the range a converter
+// can handle.
+var ErrOutOfRange = errors.New("value out of range")
+
+// Reader is implemented by anything that can read volts from an input, e.g.,
+// *ads111x.ADC.
+type Reader interface {
+	ReadVolts(input ads111x.AIN) (float64, error)
+}
+
+// Converter converts a value from one unit to another.
+type Converter interface {
+	// Convert converts v to the converter's unit.
+	Convert(v float64) (float64, error)
+	// Unit returns the label of the unit Convert returns, e.g., "V" or "°C".
+	Unit() string
+}
+
+// Reading is the result of reading a sensor.
+type Reading struct {
+	// Volts is the voltage measured at the ADC input.
+	Volts float64
+	// Value is Volts converted to engineering units.
+	Value float64
+	// Unit is the label for Value.
+	Unit string
+}
+
+func (r Reading) String() string {
+	return fmt.Sprintf("%g %s", r.Value, r.Unit)
+}
+
+// Sensor is an ADC input with a transfer function applied to its readings.
+type Sensor struct {
+	r     Reader
+	input ads111x.AIN
+	conv  Converter
+}
+
+// New returns a sensor that reads input from r and applies the converters
+// in order. With no converters, the sensor reports volts.
+func New(r Reader, input ads111x.AIN, conv ...Converter) *Sensor {
+	return &Sensor{
+		r:     r,
+		input: input,
+		conv:  Chain(conv...),
+	}
+}
+
+// Input returns the ADC input the sensor reads.
+func (s *Sensor) Input() ads111x.AIN {
+	return s.input
+}
+
+// Unit returns the label of the unit the sensor reports.
+func (s *Sensor) Unit() string {
+	return s.conv.Unit()
+}
+
+// Read reads the input and converts it to engineering units.
+func (s *Sensor) Read() (Reading, error) {
+	v, err := s.r.ReadVolts(s.input)
+	if err != nil {
+		return Reading{}, err
+	}
+	return s.Convert(v)
+}
+
+// Convert converts a voltage already read from the sensor's input.
+func (s *Sensor) Convert(volts float64) (Reading, error) {
+	val, err := s.conv.Convert(volts)
+	if err != nil {
+		return Reading{Volts: volts, Unit: s.conv.Unit()}, err
+	}
+	return Reading{Volts: volts, Value: val, Unit: s.conv.Unit()}, nil
+}
+
+type chain []Converter
+
+// Chain returns a converter that applies each converter in order, feeding
+// the output of one into the next. An empty chain reports volts unchanged.
+func Chain(conv ...Converter) Converter {
+	if len(conv) == 1 {
+		return conv[0]
+	}
+	return chain(conv)
+}
+
+func (c chain) Convert(v float64) (float64, error) {
+	var err error
+	for _, conv := range c {
+		if v, err = conv.Convert(v); err != nil {
+			return 0, err
+		}
+	}
+	return v, nil
+}
+
+func (c chain) Unit() string {
+	if len(c) == 0 {
+		return "V"
+	}
+	return c[len(c)-1].Unit()
+}
+
+// Divider converts the voltage measured across the bottom resistor of a
+// resistive divider to the voltage applied to the top of the divider.
+type Divider struct {
+	// Top is the resistance between the source and the ADC input.
+	Top float64
+	// Bottom is the resistance between the ADC input and ground.
+	Bottom float64
+}
+
+// Convert returns the source voltage for v volts at the ADC input.
+func (d Divider) Convert(v float64) (float64, error) {
+	if d.Bottom <= 0 || d.Top < 0 {
+		return 0, fmt.Errorf("invalid divider %g/%g", d.Top, d.Bottom)
+	}
+	return v * (d.Top + d.Bottom) / d.Bottom, nil
+}
+
+// Unit returns "V".
+func (d Divider) Unit() string { return "V" }
+
+// Shunt converts the voltage across a shunt resistor to current.
+type Shunt struct {
+	// Ohms is the resistance of the shunt.
+	Ohms float64
+}
+
+// Convert returns the current in amps for v volts across the shunt.
+func (s Shunt) Convert(v float64) (float64, error) {
+	if s.Ohms <= 0 {
+		return 0, fmt.Errorf("invalid shunt %g ohms", s.Ohms)
+	}
+	return v / s.Ohms, nil
+}
+
+// Unit returns "A".
+func (s Shunt) Unit() string { return "A" }
+
+// Loop limits for a 4-20 mA current loop. Currents outside of LoopFaultLow
+// and LoopFaultHigh indicate a broken loop or a failed transmitter (NAMUR NE43).
+const (
+	LoopLow       = 0.004
+	LoopHigh      = 0.020
+	LoopFaultLow  = 0.0036
+	LoopFaultHigh = 0.021
+)
+
+// Loop converts the voltage across the shunt of a 4-20 mA current loop to
+// the process value the transmitter is ranged for.
+type Loop struct {
+	// Ohms is the resistance of the shunt.
+	Ohms float64
+	// Min is the process value at 4 mA.
+	Min float64
+	// Max is the process value at 20 mA.
+	Max float64
+	// Units is the label of the process value.
+	Units string
+}
+
+// Convert returns the process value for v volts across the shunt. It returns
+// ErrOutOfRange if the loop current indicates a fault.
+func (l Loop) Convert(v float64) (float64, error) {
+	i, err := Shunt{Ohms: l.Ohms}.Convert(v)
+	if err != nil {
+		return 0, err
+	}
+	if i < LoopFaultLow || i > LoopFaultHigh {
+		return 0, fmt.Errorf("loop current %.2f mA: %w", i*1000, ErrOutOfRange)
+	}
+	return l.Min + (i-LoopLow)*(l.Max-l.Min)/(LoopHigh-LoopLow), nil
+}
+
+// Unit returns the label of the process value.
+func (l Loop) Unit() string { return l.Units }
+
+// Resistance converts the voltage at the midpoint of a divider made of a
+// known series resistor and an unknown resistance (e.g., a thermistor) to
+// the unknown resistance in ohms.
+type Resistance struct {
+	// Supply is the voltage across the whole divider.
+	Supply float64
+	// Series is the resistance of the known resistor.
+	Series float64
+	// HighSide is true when the unknown resistance is between the supply and
+	// the ADC input, and false when it's between the ADC input and ground.
+	HighSide bool
+}
+
+// Convert returns the unknown resistance for v volts at the midpoint.
+func (r Resistance) Convert(v float64) (float64, error) {
+	if v <= 0 || v >= r.Supply {
+		return 0, fmt.Errorf("%g V outside of (0, %g): %w", v, r.Supply, ErrOutOfRange)
+	}
+	if r.HighSide {
+		return r.Series * (r.Supply - v) / v, nil
+	}
+	return r.Series * v / (r.Supply - v), nil
+}
+
+// Unit returns "Ω".
+func (r Resistance) Unit() string { return "Ω" }
+
+// kelvin is 0 °C in kelvin.
+const kelvin = 273.15
+
+// Beta converts the resistance of an NTC thermistor to temperature using the
+// Beta parameter equation.
+type Beta struct {
+	// R0 is the resistance at T0.
+	R0 float64
+	// T0 is the reference temperature in °C, usually 25.
+	T0 float64
+	// B is the Beta coefficient in kelvin.
+	B float64
+}
+
+// Convert returns the temperature in °C for a resistance in ohms.
+func (b Beta) Convert(ohms float64) (float64, error) {
+	if ohms <= 0 || b.R0 <= 0 || b.B == 0 {
+		return 0, fmt.Errorf("invalid resistance %g ohms: %w", ohms, ErrOutOfRange)
+	}
+	inv := 1/(b.T0+kelvin) + math.Log(ohms/b.R0)/b.B
+	return 1/inv - kelvin, nil
+}
+
+// Unit returns "°C".
+func (b Beta) Unit() string { return "°C" }
+
+// SteinhartHart converts the resistance of an NTC thermistor to temperature
+// using the Steinhart-Hart equation: 1/T = A + B*ln(R) + C*ln(R)^3.
+type SteinhartHart struct {
+	A, B, C float64
+}
+
+// Convert returns the temperature in °C for a resistance in ohms.
+func (s SteinhartHart) Convert(ohms float64) (float64, error) {
+	if ohms <= 0 {
+		return 0, fmt.Errorf("invalid resistance %g ohms: %w", ohms, ErrOutOfRange)
+	}
+	ln := math.Log(ohms)
+	inv := s.A + s.B*ln + s.C*ln*ln*ln
+	if inv <= 0 {
+		return 0, fmt.Errorf("resistance %g ohms: %w", ohms, ErrOutOfRange)
+	}
+	return 1/inv - kelvin, nil
+}
+
+// Unit returns "°C".
+func (s SteinhartHart) Unit() string { return "°C" }
+
+// Linear applies y = Gain*x + Offset.
+type Linear struct {
+	Gain   float64
+	Offset float64
+	// Units is the label of the result.
+	Units string
+}
+
+// Map returns a Linear that maps inLo..inHi to outLo..outHi.
+func Map(inLo, inHi, outLo, outHi float64, unit string) Linear {
+	gain := (outHi - outLo) / (inHi - inLo)
+	return Linear{
+		Gain:   gain,
+		Offset: outLo - inLo*gain,
+		Units:  unit,
+	}
+}
+
+// Convert returns Gain*v + Offset.
+func (l Linear) Convert(v float64) (float64, error) {
+	return l.Gain*v + l.Offset, nil
+}
+
+// Unit returns the label of the result.
+func (l Linear) Unit() string { return l.Units }
+
+// Point is an input/output pair in a lookup table.
+type Point struct {
+	In, Out float64
+}
+
+// Table converts values by linear interpolation between points in a lookup
+// table. Inputs outside of the table return ErrOutOfRange.
+type Table struct {
+	points []Point
+	unit   string
+}
+
+// NewTable returns a lookup table for the points, which don't need to be
+// sorted. At least two points with distinct inputs are required.
+func NewTable(unit string, points ...Point) (*Table, error) {
+	if len(points) < 2 {
+		return nil, fmt.Errorf("table needs at least 2 points, got %d", len(points))
+	}
+	p := append([]Point(nil), points...)
+	sort.Slice(p, func(i, j int) bool { return p[i].In < p[j].In })
+	for i := 1; i < len(p); i++ {
+		if p[i].In == p[i-1].In {
+			return nil, fmt.Errorf("duplicate table input %g", p[i].In)
+		}
+	}
+	return &Table{points: p, unit: unit}, nil
+}
+
+// Convert returns the interpolated output for v.
+func (t *Table) Convert(v float64) (float64, error) {
+	p := t.points
+	if v < p[0].In || v > p[len(p)-1].In {
+		return 0, fmt.Errorf("%g outside of [%g, %g]: %w", v, p[0].In, p[len(p)-1].In, ErrOutOfRange)
+	}
+	i := sort.Search(len(p), func(i int) bool { return p[i].In >= v })
+	if i == 0 {
+		return p[0].Out, nil
+	}
+	a, b := p[i-1], p[i]
+	return a.Out + (v-a.In)*(b.Out-a.Out)/(b.In-a.In), nil
+}
+
+// Unit returns the label of the table's outputs.
+func (t *Table) Unit() string { return t.unit }
@@ -0,0 +1,153 @@
+package sensors
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+	"github.com/dgnorton/ads111x/internal/fakedev"
+)
+
+func Test_Sensor(t *testing.T) {
+	r := &mockReader{volts: map[ads111x.AIN]float64{ads111x.AIN_1_GND: 1.2}}
+	s := New(r, ads111x.AIN_1_GND, Divider{Top: 30000, Bottom: 10000})
+
+	if got, err := s.Read(); err != nil {
+		t.Fatal(err)
+	} else if !near(got.Value, 4.8) || got.Volts != 1.2 || got.Unit != "V" {
+		t.Fatalf("exp = 4.8 V, got = %v", got)
+	}
+
+	// No converters reports volts.
+	s = New(r, ads111x.AIN_1_GND)
+	if got, err := s.Read(); err != nil {
+		t.Fatal(err)
+	} else if got.Value != 1.2 || got.Unit != "V" {
+		t.Fatalf("exp = 1.2 V, got = %v", got)
+	}
+
+	r.err = errors.New("failed")
+	if _, err := s.Read(); err != r.err {
+		t.Fatalf("exp = %v, got = %v", r.err, err)
+	}
+}
+
+func Test_Loop(t *testing.T) {
+	l := Loop{Ohms: 100, Min: 0, Max: 10, Units: "bar"}
+	test := func(v, exp float64) {
+		if got, err := l.Convert(v); err != nil {
+			t.Fatal(err)
+		} else if !near(got, exp) {
+			t.Fatalf("exp = %f, got = %f", exp, got)
+		}
+	}
+	test(0.4, 0)
+	test(1.2, 5)
+	test(2.0, 10)
+
+	if _, err := l.Convert(0.1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("exp = %v, got = %v", ErrOutOfRange, err)
+	}
+}
+
+func Test_Thermistor(t *testing.T) {
+	// 10k NTC on the low side of a 10k divider at 3.3 V reads 1.65 V at 25 °C.
+	therm := Chain(
+		Resistance{Supply: 3.3, Series: 10000},
+		Beta{R0: 10000, T0: 25, B: 3950},
+	)
+	if got, err := therm.Convert(1.65); err != nil {
+		t.Fatal(err)
+	} else if !near(got, 25) {
+		t.Fatalf("exp = 25, got = %f", got)
+	} else if therm.Unit() != "°C" {
+		t.Fatalf("exp = °C, got = %s", therm.Unit())
+	}
+
+	// Coefficients for a common 10k NTC.
+	sh := SteinhartHart{A: 1.009249522e-03, B: 2.378405444e-04, C: 2.019202697e-07}
+	if got, err := sh.Convert(10000); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got-25) > 0.5 {
+		t.Fatalf("exp = ~25, got = %f", got)
+	}
+
+	if _, err := therm.Convert(3.3); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("exp = %v, got = %v", ErrOutOfRange, err)
+	}
+}
+
+func Test_Map(t *testing.T) {
+	l := Map(0.5, 4.5, 0, 100, "psi")
+	if got, _ := l.Convert(2.5); !near(got, 50) {
+		t.Fatalf("exp = 50, got = %f", got)
+	}
+}
+
+func Test_Table(t *testing.T) {
+	tbl, err := NewTable("%", Point{3, 100}, Point{1, 0}, Point{2, 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test := func(v, exp float64) {
+		if got, err := tbl.Convert(v); err != nil {
+			t.Fatal(err)
+		} else if !near(got, exp) {
+			t.Fatalf("exp = %f, got = %f", exp, got)
+		}
+	}
+	test(1, 0)
+	test(1.5, 40)
+	test(2.5, 90)
+	test(3, 100)
+
+	if _, err := tbl.Convert(3.1); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("exp = %v, got = %v", ErrOutOfRange, err)
+	}
+	if _, err := NewTable("%", Point{1, 0}, Point{1, 1}); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+type mockReader struct {
+	volts map[ads111x.AIN]float64
+	err   error
+}
+
+func (m *mockReader) ReadVolts(input ads111x.AIN) (float64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.volts[input], nil
+}
+
+// scaledReader reads each input with its own scale, setting it first.
+type scaledReader struct {
+	adc    *ads111x.ADC
+	scales map[ads111x.AIN]ads111x.Scale
+}
+
+func (r scaledReader) ReadVolts(input ads111x.AIN) (float64, error) {
+	if err := r.adc.SetScale(r.scales[input]); err != nil {
+		return 0, err
+	}
+	return r.adc.ReadVolts(input)
+}
+
+// newFakeADC returns an ADC whose inputs are at volts.
+func newFakeADC(t *testing.T, volts map[ads111x.AIN]float64) *ads111x.ADC {
+	d := fakedev.New()
+	for in, v := range volts {
+		d.SetVolts(uint16(in), v)
+	}
+	adc, err := ads111x.NewBus(d).OpenADC(ads111x.Addr48)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return adc
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
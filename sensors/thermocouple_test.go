@@ -0,0 +1,89 @@
+package sensors
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/dgnorton/ads111x"
+)
+
+func Test_ThermocoupleType(t *testing.T) {
+	// Values from the NIST ITS-90 thermocouple tables.
+	tests := []struct {
+		typ ThermocoupleType
+		c   float64
+		mv  float64
+	}{
+		{TypeK, -100, -3.554},
+		{TypeK, 100, 4.096},
+		{TypeK, 500, 20.644},
+		{TypeK, 1000, 41.276},
+		{TypeJ, -100, -4.633},
+		{TypeJ, 100, 5.269},
+		{TypeJ, 1000, 57.953},
+		{TypeT, -100, -3.379},
+		{TypeT, 100, 4.279},
+		{TypeT, 300, 14.862},
+		{TypeE, -100, -5.237},
+		{TypeE, 100, 6.319},
+		{TypeE, 500, 37.005},
+	}
+	for _, tt := range tests {
+		if mv, err := tt.typ.EMF(tt.c); err != nil {
+			t.Fatal(err)
+		} else if math.Abs(mv-tt.mv) > 0.001 {
+			t.Fatalf("type %v at %v °C: exp = %f mV, got = %f mV", tt.typ, tt.c, tt.mv, mv)
+		}
+		if c, err := tt.typ.Temperature(tt.mv); err != nil {
+			t.Fatal(err)
+		} else if math.Abs(c-tt.c) > 0.1 {
+			t.Fatalf("type %v at %v mV: exp = %f °C, got = %f °C", tt.typ, tt.mv, tt.c, c)
+		}
+	}
+
+	if _, err := TypeT.Temperature(30); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("exp = %v, got = %v", ErrOutOfRange, err)
+	}
+}
+
+func Test_Thermocouple(t *testing.T) {
+	// 4.096 mV - 1.000 mV (25 °C cold junction) on a type K thermocouple.
+	r := &mockReader{volts: map[ads111x.AIN]float64{
+		ads111x.AIN_0_1:   (4.096 - 1.000) / 1000,
+		ads111x.AIN_3_GND: 1.65,
+	}}
+	cj := SensorJunction(New(r, ads111x.AIN_3_GND,
+		Resistance{Supply: 3.3, Series: 10000},
+		Beta{R0: 10000, T0: 25, B: 3950},
+	))
+	tc := NewThermocouple(r, ads111x.AIN_0_1, TypeK, cj)
+
+	if got, err := tc.Read(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got.Value-100) > 0.1 {
+		t.Fatalf("exp = 100 °C, got = %v", got)
+	}
+
+	// The thermocouple and the cold junction thermistor on one ADC, each
+	// read with its own scale.
+	adc := newFakeADC(t, r.volts)
+	sr := scaledReader{adc: adc, scales: map[ads111x.AIN]ads111x.Scale{
+		ads111x.AIN_0_1:   ads111x.Scale_0_256V,
+		ads111x.AIN_3_GND: ads111x.Scale_4_096V,
+	}}
+	cj = SensorJunction(New(sr, ads111x.AIN_3_GND,
+		Resistance{Supply: 3.3, Series: 10000},
+		Beta{R0: 10000, T0: 25, B: 3950},
+	))
+	if got, err := NewThermocouple(sr, ads111x.AIN_0_1, TypeK, cj).Read(); err != nil {
+		t.Fatal(err)
+	} else if math.Abs(got.Value-100) > 0.1 {
+		t.Fatalf("exp = 100 °C, got = %v", got)
+	}
+
+	tc = NewThermocouple(r, ads111x.AIN_0_1, TypeK, SensorJunction(New(r, ads111x.AIN_3_GND)))
+	if _, err := tc.Read(); err == nil {
+		t.Fatal("expected error for cold junction sensor in volts")
+	}
+}
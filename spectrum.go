@@ -0,0 +1,281 @@
+package ads111x
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// WindowFunc is a window function applied to samples before computing a
+// spectrum.
+type WindowFunc int
+
+const (
+	// Rectangular applies no window. Best frequency resolution, worst leakage.
+	Rectangular WindowFunc = iota
+	// Hann is a good general purpose window.
+	Hann
+	// Hamming has a narrower main lobe than Hann but higher far side lobes.
+	Hamming
+	// FlatTop has the most accurate amplitudes but the widest main lobe.
+	FlatTop
+)
+
+func (w WindowFunc) String() string {
+	switch w {
+	case Rectangular:
+		return "rectangular"
+	case Hann:
+		return "hann"
+	case Hamming:
+		return "hamming"
+	case FlatTop:
+		return "flattop"
+	default:
+		return fmt.Sprintf("WindowFunc(%d)", int(w))
+	}
+}
+
+// coefficients returns the cosine sum coefficients for the window.
+func (w WindowFunc) coefficients() []float64 {
+	switch w {
+	case Hann:
+		return []float64{0.5, 0.5}
+	case Hamming:
+		return []float64{0.54, 0.46}
+	case FlatTop:
+		return []float64{0.21557895, 0.41663158, 0.277263158, 0.083578947, 0.006947368}
+	default:
+		return []float64{1}
+	}
+}
+
+// lobe returns the half width, in bins, of the window's main lobe.
+func (w WindowFunc) lobe() int {
+	switch w {
+	case Hann, Hamming:
+		return 2
+	case FlatTop:
+		return 5
+	default:
+		return 1
+	}
+}
+
+// weights returns the window's weights for n samples.
+func (w WindowFunc) weights(n int) []float64 {
+	a := w.coefficients()
+	ws := make([]float64, n)
+	for i := range ws {
+		var v float64
+		for k, ak := range a {
+			term := ak * math.Cos(2*math.Pi*float64(k)*float64(i)/float64(n))
+			if k%2 == 1 {
+				term = -term
+			}
+			v += term
+		}
+		ws[i] = v
+	}
+	return ws
+}
+
+// Spectrum is the single sided amplitude spectrum of a window of samples.
+type Spectrum struct {
+	// Window is the window function that was applied.
+	Window WindowFunc
+	// Rate is the sample rate in samples per second.
+	Rate float64
+	// BinWidth is the frequency spacing of the bins in Hz.
+	BinWidth float64
+	// Magnitude is the amplitude in volts of each bin from 0 Hz to Rate / 2.
+	Magnitude []float64
+	// power is |X[k]|^2 of each bin, used for SNR estimates.
+	power []float64
+}
+
+// Peak is a local maximum in a spectrum.
+type Peak struct {
+	Bin int
+	// Frequency is interpolated between bins.
+	Frequency float64
+	// Magnitude is the amplitude in volts.
+	Magnitude float64
+}
+
+// ComputeSpectrum applies the window to the samples and returns their
+// spectrum. Samples are zero padded to a power of two.
+func ComputeSpectrum(s *Samples, w WindowFunc) (*Spectrum, error) {
+	n := len(s.Volts)
+	if n < 4 {
+		return nil, errors.New("need at least 4 samples")
+	}
+
+	ws := w.weights(n)
+	var gain float64
+	for _, x := range ws {
+		gain += x
+	}
+
+	// Remove the mean before windowing so DC leakage doesn't swamp low
+	// frequency bins, then add it back as bin 0.
+	var mean float64
+	for _, v := range s.Volts {
+		mean += v
+	}
+	mean /= float64(n)
+
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	x := make([]complex128, size)
+	for i, v := range s.Volts {
+		x[i] = complex((v-mean)*ws[i], 0)
+	}
+	fft(x)
+
+	rate := s.MeasuredRate()
+	bins := size/2 + 1
+	sp := &Spectrum{
+		Window:    w,
+		Rate:      rate,
+		BinWidth:  rate / float64(size),
+		Magnitude: make([]float64, bins),
+		power:     make([]float64, bins),
+	}
+	for k := 0; k < bins; k++ {
+		a := cmplx.Abs(x[k])
+		sp.power[k] = a * a
+		sp.Magnitude[k] = 2 * a / gain
+	}
+	sp.Magnitude[0] = math.Abs(mean)
+	sp.power[0] = 0
+	return sp, nil
+}
+
+// Frequency returns the center frequency of a bin.
+func (sp *Spectrum) Frequency(bin int) float64 {
+	return float64(bin) * sp.BinWidth
+}
+
+// Peaks returns up to n of the largest local maxima, excluding DC, sorted by
+// magnitude with the largest first.
+func (sp *Spectrum) Peaks(n int) []Peak {
+	m := sp.Magnitude
+	var peaks []Peak
+	for k := 1; k < len(m); k++ {
+		if m[k] <= m[k-1] || (k+1 < len(m) && m[k] < m[k+1]) {
+			continue
+		}
+		p := Peak{Bin: k, Frequency: sp.Frequency(k), Magnitude: m[k]}
+		// Parabolic interpolation of the peak's position between bins.
+		if k+1 < len(m) {
+			a, b, c := m[k-1], m[k], m[k+1]
+			if d := a - 2*b + c; d != 0 {
+				p.Frequency += 0.5 * (a - c) / d * sp.BinWidth
+			}
+		}
+		peaks = append(peaks, p)
+	}
+	sort.Slice(peaks, func(i, j int) bool { return peaks[i].Magnitude > peaks[j].Magnitude })
+	if len(peaks) > n {
+		peaks = peaks[:n]
+	}
+	return peaks
+}
+
+// Harmonics is the number of harmonics of the fundamental excluded from
+// noise when computing SNR.
+const Harmonics = 5
+
+// SNR returns the ratio of the fundamental (the largest peak) to the noise,
+// excluding DC and harmonics, in dB.
+func (sp *Spectrum) SNR() float64 {
+	sig, noise, _ := sp.powers()
+	return 10 * math.Log10(sig/noise)
+}
+
+// SINAD returns the ratio of the fundamental to noise plus distortion in dB.
+func (sp *Spectrum) SINAD() float64 {
+	sig, noise, dist := sp.powers()
+	return 10 * math.Log10(sig/(noise+dist))
+}
+
+// ENOB returns the effective number of bits estimated from SINAD. It only
+// makes sense for a clean, near full scale sine input.
+func (sp *Spectrum) ENOB() float64 {
+	return (sp.SINAD() - 1.76) / 6.02
+}
+
+// powers returns the power in the fundamental, noise and harmonics.
+func (sp *Spectrum) powers() (sig, noise, dist float64) {
+	peaks := sp.Peaks(1)
+	if len(peaks) == 0 {
+		return 0, 0, 0
+	}
+	fund := peaks[0].Bin
+	lobe := sp.Window.lobe()
+	size := 2 * (len(sp.power) - 1)
+
+	class := make([]int, len(sp.power)) // 0 = noise, 1 = signal, 2 = harmonic, 3 = DC
+	mark := func(center, c int) {
+		for k := center - lobe; k <= center+lobe; k++ {
+			if k >= 0 && k < len(class) && class[k] == 0 {
+				class[k] = c
+			}
+		}
+	}
+	mark(0, 3)
+	mark(fund, 1)
+	for h := 2; h <= Harmonics+1; h++ {
+		// Harmonics above Nyquist alias back into the spectrum.
+		k := (h * fund) % size
+		if k > size/2 {
+			k = size - k
+		}
+		mark(k, 2)
+	}
+
+	for k, p := range sp.power {
+		switch class[k] {
+		case 0:
+			noise += p
+		case 1:
+			sig += p
+		case 2:
+			dist += p
+		}
+	}
+	return sig, noise, dist
+}
+
+// fft computes the discrete Fourier transform of x in place. len(x) must be
+// a power of two.
+func fft(x []complex128) {
+	n := len(x)
+	// Bit reversal permutation.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				a, b := x[start+k], x[start+k+size/2]*w
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				w *= step
+			}
+		}
+	}
+}
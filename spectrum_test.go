@@ -0,0 +1,78 @@
+package ads111x
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func Test_ComputeSpectrum(t *testing.T) {
+	v := sine(1000, 860, 100, 1, 0.5)
+	for i, x := range sine(1000, 860, 250, 0.1, 0) {
+		v[i] += x
+	}
+	s := &Samples{DataRate: DR_860SPS, Volts: v}
+
+	sp, err := ComputeSpectrum(s, FlatTop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sp.Magnitude) != 513 || sp.BinWidth != 860.0/1024 {
+		t.Fatalf("exp = 513 bins %f Hz wide, got = %d bins %f Hz wide", 860.0/1024, len(sp.Magnitude), sp.BinWidth)
+	}
+	if math.Abs(sp.Magnitude[0]-0.5) > 0.01 {
+		t.Fatalf("exp = 0.5 V DC, got = %f", sp.Magnitude[0])
+	}
+
+	peaks := sp.Peaks(2)
+	if len(peaks) != 2 {
+		t.Fatalf("exp = 2 peaks, got = %d", len(peaks))
+	}
+	check := func(p Peak, freq, mag float64) {
+		if math.Abs(p.Frequency-freq) > sp.BinWidth/2 || math.Abs(p.Magnitude-mag) > mag*0.01 {
+			t.Fatalf("exp = %f V at %f Hz, got = %f V at %f Hz", mag, freq, p.Magnitude, p.Frequency)
+		}
+	}
+	check(peaks[0], 100, 1)
+	check(peaks[1], 250, 0.1)
+}
+
+func Test_Spectrum_SNR(t *testing.T) {
+	// A sine with a whole number of cycles in the window so there's no
+	// leakage, plus 1 mV RMS of noise: SNR = 10*log10(0.5 / 1e-6) = 57 dB.
+	rng := rand.New(rand.NewSource(1))
+	v := sine(1024, 860, 860*37/1024.0, 1, 0)
+	for i := range v {
+		v[i] += rng.NormFloat64() * 0.001
+	}
+	sp, err := ComputeSpectrum(&Samples{DataRate: DR_860SPS, Volts: v}, Rectangular)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snr := sp.SNR(); math.Abs(snr-57) > 1 {
+		t.Fatalf("exp = ~57 dB, got = %f dB", snr)
+	}
+
+	// A full scale sine quantized by the ADC has an ENOB close to 16 bits.
+	v = sine(1024, 860, 860*37/1024.0, 2.047, 0)
+	for i := range v {
+		v[i] = Volts(uint16(int16(math.Round(v[i]/4.096*Resolution))), Scale_2_048V)
+	}
+	sp, err = ComputeSpectrum(&Samples{DataRate: DR_860SPS, Volts: v}, Rectangular)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enob := sp.ENOB(); enob < 15.5 || enob > 16.5 {
+		t.Fatalf("exp = ~16 bits, got = %f", enob)
+	}
+}
+
+func Test_FFT(t *testing.T) {
+	x := []complex128{1, 0, 0, 0, 0, 0, 0, 0}
+	fft(x)
+	for _, v := range x {
+		if v != 1 {
+			t.Fatalf("exp = all 1, got = %v", x)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ads111x
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// syncPoll is how long a synchronized acquisition sleeps between polls of
+// the devices' status.
+const syncPoll = 100 * time.Microsecond
+
+// SyncInput is an input on one ADC of a synchronized acquisition.
+type SyncInput struct {
+	ADC   *ADC
+	Input AIN
+}
+
+// SyncSample is one ADC's conversion in a Frame.
+type SyncSample struct {
+	SyncInput
+	Raw   int16
+	Volts float64
+	// Start is when the conversion was started and Finish is when the
+	// device was first seen idle afterward, so Finish is late by up to one
+	// status poll.
+	Start  time.Time
+	Finish time.Time
+}
+
+// Frame is a set of conversions started back to back on several ADCs.
+type Frame struct {
+	// Samples are in the order of the sampler's inputs.
+	Samples []SyncSample
+	// Skew is the time between the first and last conversion starts.
+	Skew time.Duration
+}
+
+// SyncSampler makes synchronized acquisitions across ADCs. It isn't safe
+// for concurrent use, and the ADCs shouldn't be used by anything else while
+// it's acquiring.
+type SyncSampler struct {
+	inputs []SyncInput
+	cfgs   []uint16 // config that starts each input's conversion
+}
+
+// NewSyncSampler returns a sampler for one input on each of the ADCs. It
+// reads each ADC's config once; the scale and data rate set then are used
+// for every frame.
+func NewSyncSampler(inputs ...SyncInput) (*SyncSampler, error) {
+	if len(inputs) == 0 {
+		return nil, errors.New("no inputs")
+	}
+	s := &SyncSampler{inputs: inputs}
+	seen := make(map[*ADC]bool)
+	for _, in := range inputs {
+		if seen[in.ADC] {
+			return nil, fmt.Errorf("more than one input on an ADC: %v", in.Input)
+		}
+		seen[in.ADC] = true
+		cfg, err := in.ADC.Config()
+		if err != nil {
+			return nil, err
+		}
+		// Single shot mode, with the status bit set to start a conversion.
+		cfg = cfg&^(AIN_Mask|Mode_Mask) | uint16(in.Input) | uint16(Single) | Status_Mask
+		s.cfgs = append(s.cfgs, cfg)
+	}
+	return s, nil
+}
+
+// Frame starts a conversion on every ADC back to back, waits for them all to
+// finish, then reads them.
+func (s *SyncSampler) Frame(ctx context.Context) (Frame, error) {
+	f := Frame{Samples: make([]SyncSample, len(s.inputs))}
+	for i, in := range s.inputs {
+		if err := in.ADC.WriteConfig(s.cfgs[i]); err != nil {
+			return Frame{}, err
+		}
+		f.Samples[i] = SyncSample{SyncInput: in, Start: now()}
+	}
+	f.Skew = f.Samples[len(s.inputs)-1].Start.Sub(f.Samples[0].Start)
+
+	// Wait for the fastest conversion before polling.
+	var wait, timeout time.Duration
+	for i, cfg := range s.cfgs {
+		ct := ConversionTime(DataRate(cfg & DataRate_Mask))
+		if i == 0 || ct < wait {
+			wait = ct
+		}
+		if d := ct + time.Duration(float64(ct)*DataRateTolerance); d > timeout {
+			timeout = d
+		}
+	}
+	sleep(wait)
+	deadline := f.Samples[len(s.inputs)-1].Start.Add(2 * timeout)
+
+	pending := len(s.inputs)
+	for pending > 0 {
+		for i := range f.Samples {
+			smp := &f.Samples[i]
+			if !smp.Finish.IsZero() {
+				continue
+			}
+			st, err := smp.ADC.Status()
+			if err != nil {
+				return Frame{}, err
+			}
+			if st == Idle {
+				smp.Finish = now()
+				pending--
+			}
+		}
+		if pending == 0 {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return Frame{}, err
+		}
+		if now().After(deadline) {
+			return Frame{}, errors.New("timed out waiting for conversions")
+		}
+		sleep(syncPoll)
+	}
+
+	for i := range f.Samples {
+		smp := &f.Samples[i]
+		cnt, err := smp.ADC.ReadRegUint16(ConversionReg)
+		if err != nil {
+			return Frame{}, err
+		}
+		smp.Raw = int16(cnt)
+		smp.Volts = Volts(cnt, Scale(s.cfgs[i]&Scale_Mask))
+	}
+	return f, nil
+}
+
+// SyncRead makes one synchronized acquisition of the named channels, which
+// must be on different ADCs.
+func (m *Manager) SyncRead(ctx context.Context, names ...string) (Frame, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inputs := make([]SyncInput, 0, len(names))
+	for _, name := range names {
+		ch, ok := m.channels[name]
+		if !ok {
+			return Frame{}, fmt.Errorf("unknown channel %q", name)
+		}
+		inputs = append(inputs, SyncInput{ADC: m.adcs[ch.Addr], Input: ch.Input})
+	}
+	s, err := NewSyncSampler(inputs...)
+	if err != nil {
+		return Frame{}, err
+	}
+	return s.Frame(ctx)
+}
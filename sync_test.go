@@ -0,0 +1,68 @@
+package ads111x
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_SyncSampler(t *testing.T) {
+	c := useFakeClock()
+	defer c.restore()
+
+	fb := newFakeBus(0x48, 0x49)
+	fb.Device(0x48).SetReg(ConversionReg, 0x4000)
+	fb.Device(0x49).SetReg(ConversionReg, 0xc000)
+	fb.Device(0x49).SetBusyPolls(3)
+	m, err := NewManager(NewBus(fb), Addr48, Addr49)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	f, err := m.SyncRead(context.Background(), "ch0", "ch6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Samples) != 2 {
+		t.Fatalf("exp = 2 samples, got = %d", len(f.Samples))
+	}
+	s0, s1 := f.Samples[0], f.Samples[1]
+	if s0.Volts != 1.024 || s1.Volts != -1.024 || s1.Input != AIN_2_GND {
+		t.Fatalf("unexpected samples: %+v", f.Samples)
+	}
+	// Both conversions were started before waiting.
+	if f.Skew != 0 {
+		t.Fatalf("exp = 0, got = %v", f.Skew)
+	}
+	// The first device finished after the nominal conversion time and the
+	// second three polls later.
+	ct := ConversionTime(DR_128SPS)
+	if d := s0.Finish.Sub(s0.Start); d != ct {
+		t.Fatalf("exp = %v, got = %v", ct, d)
+	}
+	if d := s1.Finish.Sub(s1.Start); d != ct+3*syncPoll {
+		t.Fatalf("exp = %v, got = %v", ct+3*syncPoll, d)
+	}
+	// The conversions were started in single shot mode.
+	if cfg := fb.Device(0x49).Reg(ConfigReg); Mode(cfg&Mode_Mask) != Single || AIN(cfg&AIN_Mask) != AIN_2_GND {
+		t.Fatalf("unexpected config: 0x%x", cfg)
+	}
+
+	fb.Device(0x48).SetBusyPolls(1 << 20)
+	if _, err := m.SyncRead(context.Background(), "ch0"); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if _, err := m.SyncRead(context.Background(), "ch0", "ch1"); err == nil {
+		t.Fatal("expected error for two inputs on one ADC")
+	}
+
+	// A real clock gives increasing start times.
+	c.restore()
+	fb.Device(0x48).SetBusyPolls(0)
+	if f, err := m.SyncRead(context.Background(), "ch0", "ch4"); err != nil {
+		t.Fatal(err)
+	} else if f.Skew < 0 || f.Skew > time.Second {
+		t.Fatalf("unexpected skew: %v", f.Skew)
+	}
+}